@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io"
+	"strings"
+
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+func authorGet(a *app, args []string) error {
+	fs := a.flagSet("author get")
+	args, err := a.parse(fs, args, 1, 1, "<id>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	author, _, err := a.client.Authors.Get(a.ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.print(author, func(w io.Writer) {
+		row(w, "ID", author.ID)
+		row(w, "Name", author.Name)
+		var identities []string
+		for provider, name := range author.Identities {
+			identities = append(identities, provider+": "+name)
+		}
+		if len(identities) > 0 {
+			row(w, "Identities", strings.Join(identities, ", "))
+		}
+	})
+}
+
+func authorResources(a *app, args []string) error {
+	fs := a.flagSet("author resources")
+	listOpts := listFlags(fs)
+	args, err := a.parse(fs, args, 1, 1, "<id>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	opts := &spiget.ResourceListOptions{ListOptions: listOpts()}
+	resources, _, err := a.client.Authors.ListResources(a.ctx, id, opts)
+	if err != nil {
+		return err
+	}
+	return a.print(resources, resourceTable(resources))
+}
+
+func authorSearch(a *app, args []string) error {
+	fs := a.flagSet("author search")
+	listOpts := listFlags(fs)
+	args, err := a.parse(fs, args, 1, 1, "<query>")
+	if err != nil {
+		return err
+	}
+
+	opts := &spiget.AuthorSearchOptions{ListOptions: listOpts()}
+	authors, _, err := a.client.Authors.Search(a.ctx, args[0], opts)
+	if err != nil {
+		return err
+	}
+	return a.print(authors, authorTable(authors))
+}
@@ -0,0 +1,38 @@
+package main
+
+import "github.com/sunxyw/go-spiget/spiget"
+
+func categoryList(a *app, args []string) error {
+	fs := a.flagSet("category list")
+	listOpts := listFlags(fs)
+	if _, err := a.parse(fs, args, 0, 0, ""); err != nil {
+		return err
+	}
+
+	opts := &spiget.CategoryListOptions{ListOptions: listOpts()}
+	categories, _, err := a.client.Categories.List(a.ctx, opts)
+	if err != nil {
+		return err
+	}
+	return a.print(categories, categoryTable(categories))
+}
+
+func categoryResources(a *app, args []string) error {
+	fs := a.flagSet("category resources")
+	listOpts := listFlags(fs)
+	args, err := a.parse(fs, args, 1, 1, "<id>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	opts := &spiget.ResourceListOptions{ListOptions: listOpts()}
+	resources, _, err := a.client.Categories.ListResources(a.ctx, id, opts)
+	if err != nil {
+		return err
+	}
+	return a.print(resources, resourceTable(resources))
+}
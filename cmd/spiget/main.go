@@ -0,0 +1,314 @@
+// Command spiget is a command-line client for the Spiget API.
+//
+// Usage:
+//
+//	spiget [flags] <command> [<subcommand>] [flags] [args]
+//
+// Run "spiget help" for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+// Exit codes returned by the command.
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitNotFound    = 3
+	exitRateLimited = 4
+	exitUnavailable = 5
+	exitInvalid     = 6
+)
+
+const usage = `Usage: spiget [flags] <command> [<subcommand>] [flags] [args]
+
+Commands:
+  resource get|versions|updates|reviews|download <id>
+  author get|resources <id>
+  author search <query>
+  category list
+  category resources <id>
+  search <query>
+  status
+  plugins outdated
+  plugins install <id>...
+  plugins update [<id>...]
+  plugins remove <id|file>...
+  webhook register <url> <event>...
+  webhook delete <id> <secret>
+  webhook status <id>
+  webhook events
+
+Flags (accepted by all commands):
+  --base-url string   base URL of the Spiget API (default "https://api.spiget.org/v2/")
+  --json              print JSON instead of a table
+  --timeout duration  timeout of the whole command (default 30s)
+
+List commands also accept --size, --page, --sort and --fields. Arguments
+following -- are not parsed as flags, e.g. spiget search -- -query.
+
+Plugins commands manage the jars in a server's plugins directory and accept
+--dir (default "plugins"), --dry-run and --mc <version> to only install
+resources tested with that Minecraft version. Replaced and removed jars are
+moved to the .spiget-backup directory. Jars not installed by spiget are looked
+up by the name and authors in their plugin.yml. With --json, progress is
+written to stderr.
+
+Exit codes:
+  0  success
+  1  error
+  2  invalid usage
+  3  not found
+  4  rate limited
+  5  server unavailable
+  6  invalid request
+`
+
+// usageError is returned for invalid command lines.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+func usagef(format string, args ...interface{}) error {
+	return usageError(fmt.Sprintf(format, args...))
+}
+
+// app holds the global flags and the client shared by all commands.
+type app struct {
+	out     io.Writer
+	errOut  io.Writer
+	baseURL string
+	json    bool
+	timeout time.Duration
+
+	// Set by parse once all flags are known.
+	ctx    context.Context
+	cancel context.CancelFunc
+	client *spiget.Client
+}
+
+// handler runs a command with the arguments following its name. Handlers
+// parse their flags with app.parse before using the client.
+type handler func(a *app, args []string) error
+
+// commands maps command names to their subcommands. Commands without
+// subcommands are registered under the empty name.
+var commands = map[string]map[string]handler{
+	"resource": {
+		"get":      resourceGet,
+		"versions": resourceVersions,
+		"updates":  resourceUpdates,
+		"reviews":  resourceReviews,
+		"download": resourceDownload,
+	},
+	"author": {
+		"get":       authorGet,
+		"resources": authorResources,
+		"search":    authorSearch,
+	},
+	"category": {
+		"list":      categoryList,
+		"resources": categoryResources,
+	},
+	"search": {"": search},
+	"status": {"": status},
+	"plugins": {
+		"outdated": pluginsOutdated,
+		"install":  pluginsInstall,
+		"update":   pluginsUpdate,
+		"remove":   pluginsRemove,
+	},
+	"webhook": {
+		"register": webhookRegister,
+		"delete":   webhookDelete,
+		"status":   webhookStatus,
+		"events":   webhookEvents,
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	a := &app{out: stdout, errOut: stderr, timeout: 30 * time.Second}
+	fs := a.flagSet("spiget")
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	args = fs.Args()
+
+	if len(args) == 0 || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	h, rest, err := lookup(args)
+	if err == nil {
+		err = a.run(h, rest)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "spiget:", err)
+	}
+	return exitCode(err)
+}
+
+// lookup returns the handler for the command named by the leading args and
+// the remaining arguments.
+func lookup(args []string) (handler, []string, error) {
+	subs, ok := commands[args[0]]
+	if !ok {
+		return nil, nil, usagef("unknown command %q", args[0])
+	}
+	if h, ok := subs[""]; ok {
+		return h, args[1:], nil
+	}
+
+	var names []string
+	for name := range subs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(args) < 2 {
+		return nil, nil, usagef("%s requires a subcommand: %s", args[0], strings.Join(names, ", "))
+	}
+	h, ok := subs[args[1]]
+	if !ok {
+		return nil, nil, usagef("unknown %s subcommand %q, want one of: %s", args[0], args[1], strings.Join(names, ", "))
+	}
+	return h, args[2:], nil
+}
+
+func (a *app) run(h handler, args []string) error {
+	defer func() {
+		if a.cancel != nil {
+			a.cancel()
+		}
+	}()
+	return h(a, args)
+}
+
+// flagSet returns a new flag set with the global flags registered. Their
+// defaults are the values parsed so far, so global flags may be given before
+// or after the command.
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&a.baseURL, "base-url", a.baseURL, "base URL of the Spiget API")
+	fs.BoolVar(&a.json, "json", a.json, "print JSON instead of a table")
+	fs.DurationVar(&a.timeout, "timeout", a.timeout, "timeout of the whole command")
+	return fs
+}
+
+// parse parses args with fs, allowing flags to follow positional arguments
+// up to "--", and checks the number of positional arguments against minArgs
+// and maxArgs. A negative maxArgs allows any number of arguments. The client
+// and context are created with the final global flags.
+func (a *app) parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int, names string) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError(err.Error())
+		}
+		rest := fs.Args()
+		// All arguments following "--" are positional, e.g. search queries
+		// starting with "-".
+		if n := len(args) - len(rest); n > 0 && args[n-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		args = rest
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) < minArgs || (maxArgs >= 0 && len(positional) > maxArgs) {
+		return nil, usagef("usage: spiget %s %s", fs.Name(), names)
+	}
+
+	if err := a.newClient(); err != nil {
+		return nil, err
+	}
+	a.ctx, a.cancel = context.Background(), func() {}
+	if a.timeout > 0 {
+		a.ctx, a.cancel = context.WithTimeout(a.ctx, a.timeout)
+	}
+	return positional, nil
+}
+
+func (a *app) newClient() error {
+	httpClient := &http.Client{}
+	if a.baseURL == "" {
+		a.client = spiget.NewClient(httpClient)
+		return nil
+	}
+
+	c, err := spiget.NewCustomClient(a.baseURL, httpClient)
+	if err != nil {
+		return usagef("invalid --base-url: %v", err)
+	}
+	a.client = c
+	return nil
+}
+
+// listFlags registers the pagination flags on fs. The returned function
+// builds the ListOptions after parsing.
+func listFlags(fs *flag.FlagSet) func() spiget.ListOptions {
+	size := fs.Int("size", 0, "number of results per page")
+	page := fs.Int("page", 0, "page index")
+	sortBy := fs.String("sort", "", `field to sort by, prefixed with "+" or "-", e.g. -downloads`)
+	fields := fs.String("fields", "", "comma-separated list of fields to return")
+	return func() spiget.ListOptions {
+		opts := spiget.ListOptions{Size: *size, Page: *page, Sort: *sortBy}
+		for _, f := range strings.Split(*fields, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				opts.Fields = append(opts.Fields, f)
+			}
+		}
+		return opts
+	}
+}
+
+// exitCode maps err to the exit code of the command.
+func exitCode(err error) int {
+	var usageErr usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case spiget.IsNotFound(err):
+		return exitNotFound
+	case spiget.IsRateLimited(err):
+		return exitRateLimited
+	case spiget.IsServerUnavailable(err):
+		return exitUnavailable
+	case spiget.IsInvalidRequest(err):
+		return exitInvalid
+	}
+	return exitError
+}
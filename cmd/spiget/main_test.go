@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/resources/1":
+			w.Write([]byte(`{"id": 1, "name": "Foo"}`))
+		case "/v2/resources/2":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "resource not found"}`))
+		case "/v2/resources/3":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/v2/resources/4":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/v2/resources/5":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "invalid request"}`))
+		case "/v2/resources/6":
+			w.Write([]byte(`not json`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	base := "--base-url=" + server.URL + "/v2/"
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{base, "resource", "get", "1"}, exitOK},
+		{[]string{"resource", "get", "1", base, "--json"}, exitOK},
+		{[]string{base, "resource", "get", "2"}, exitNotFound},
+		{[]string{base, "resource", "get", "3"}, exitRateLimited},
+		{[]string{base, "resource", "get", "4"}, exitUnavailable},
+		{[]string{"--base-url=" + closed.URL + "/v2/", "resource", "get", "1"}, exitUnavailable},
+		{[]string{base, "resource", "get", "5"}, exitInvalid},
+		{[]string{base, "resource", "get", "6"}, exitError},
+		{[]string{"help"}, exitOK},
+		{[]string{"--help"}, exitOK},
+		{nil, exitUsage},
+		{[]string{"--unknown"}, exitUsage},
+		{[]string{"unknown"}, exitUsage},
+		{[]string{"resource"}, exitUsage},
+		{[]string{"resource", "unknown"}, exitUsage},
+		{[]string{base, "resource", "get"}, exitUsage},
+		{[]string{base, "resource", "get", "1", "2"}, exitUsage},
+		{[]string{base, "resource", "get", "abc"}, exitUsage},
+		{[]string{base, "resource", "get", "--size", "1"}, exitUsage},
+		{[]string{"--base-url=://", "resource", "get", "1"}, exitUsage},
+		{[]string{base, "search", "-query"}, exitUsage},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if got := run(tt.args, &stdout, &stderr); got != tt.want {
+			t.Errorf("run(%q) returned %d, want %d; stderr: %s", tt.args, got, tt.want, stderr.String())
+		}
+	}
+}
+
+func TestRunArgumentTerminator(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, strings.TrimPrefix(r.URL.Path, "/v2/search/resources/"))
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"search", "--base-url", server.URL + "/v2/", "--", "-query"}
+	if code := run(args, &stdout, &stderr); code != exitOK {
+		t.Fatalf("run(%q) returned %d: %s", args, code, stderr.String())
+	}
+	if len(queries) != 1 || queries[0] != "-query" {
+		t.Errorf("searched for %q, want -query", queries)
+	}
+
+	args = []string{"search", "--base-url", server.URL + "/v2/", "--", "-query", "--json"}
+	if code := run(args, &stdout, &stderr); code != exitUsage {
+		t.Errorf("run(%q) returned %d, want %d", args, code, exitUsage)
+	}
+}
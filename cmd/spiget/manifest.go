@@ -0,0 +1,234 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// manifestName is the file in the plugins directory recording which
+	// jars were installed from Spiget.
+	manifestName = ".spiget.json"
+
+	// backupDirName is the directory in the plugins directory replaced and
+	// removed jars are moved to. The server does not load jars from it.
+	backupDirName = ".spiget-backup"
+)
+
+// installedPlugin is a jar installed from Spiget.
+type installedPlugin struct {
+	File        string    `json:"file"`
+	Resource    int       `json:"resource"`
+	Name        string    `json:"name"`
+	Version     int       `json:"version"`
+	VersionName string    `json:"versionName,omitempty"`
+	Installed   time.Time `json:"installed"`
+}
+
+// manifest lists the plugins installed from Spiget.
+type manifest struct {
+	Plugins []*installedPlugin `json:"plugins"`
+}
+
+// byResource returns the plugin installed from the resource, or nil.
+func (m *manifest) byResource(id int) *installedPlugin {
+	for _, p := range m.Plugins {
+		if p.Resource == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// byFile returns the plugin installed as the jar file, or nil.
+func (m *manifest) byFile(file string) *installedPlugin {
+	for _, p := range m.Plugins {
+		if p.File == file {
+			return p
+		}
+	}
+	return nil
+}
+
+// put adds p to the manifest, replacing the entry of the same resource.
+func (m *manifest) put(p *installedPlugin) {
+	m.remove(p.Resource)
+	m.Plugins = append(m.Plugins, p)
+	sort.Slice(m.Plugins, func(i, j int) bool { return m.Plugins[i].File < m.Plugins[j].File })
+}
+
+// remove deletes the entry of the resource.
+func (m *manifest) remove(id int) {
+	kept := m.Plugins[:0]
+	for _, p := range m.Plugins {
+		if p.Resource != id {
+			kept = append(kept, p)
+		}
+	}
+	m.Plugins = kept
+}
+
+// pluginDir is a server's plugins directory.
+type pluginDir struct {
+	path     string
+	manifest *manifest
+}
+
+// openPluginDir reads the manifest of the plugins directory at path. A
+// missing manifest is treated as empty.
+func openPluginDir(path string) (*pluginDir, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, usagef("%s is not a directory", path)
+	}
+
+	d := &pluginDir{path: path, manifest: &manifest{}}
+	data, err := os.ReadFile(filepath.Join(path, manifestName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, d.manifest); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// save writes the manifest.
+func (d *pluginDir) save() error {
+	data, err := json.MarshalIndent(d.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(d.path, manifestName), append(data, '\n'), 0644)
+}
+
+// jars returns the names of all jar files in the directory.
+func (d *pluginDir) jars() ([]string, error) {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return nil, err
+	}
+	var jars []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.EqualFold(filepath.Ext(e.Name()), ".jar") {
+			jars = append(jars, e.Name())
+		}
+	}
+	return jars, nil
+}
+
+// backup moves the jar file into the backup directory and returns its new
+// path. Existing backups are never overwritten.
+func (d *pluginDir) backup(file string) (string, error) {
+	dir := filepath.Join(d.path, backupDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	base := filepath.Join(dir, file+"."+time.Now().Format("20060102-150405"))
+	dst := base
+	for i := 1; ; i++ {
+		if _, err := os.Stat(dst); errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		dst = base + "." + strconv.Itoa(i)
+	}
+	return dst, os.Rename(filepath.Join(d.path, file), dst)
+}
+
+// exists reports whether the jar file exists in the directory.
+func (d *pluginDir) exists(file string) bool {
+	_, err := os.Stat(filepath.Join(d.path, file))
+	return err == nil
+}
+
+// pluginInfo is the name, version and authors declared in the plugin.yml of
+// a jar.
+type pluginInfo struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Authors []string `json:"authors,omitempty"`
+}
+
+// readPluginInfo reads the name, version and authors from the plugin.yml of
+// the jar. Only top-level scalar keys and the authors list are supported,
+// which is all that is needed for identifying a plugin.
+func readPluginInfo(path string) (*pluginInfo, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	for _, name := range []string{"plugin.yml", "paper-plugin.yml", "bungee.yml"} {
+		f, err := zr.Open(name)
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		return parsePluginYAML(f)
+	}
+	return nil, errors.New("no plugin.yml found")
+}
+
+func parsePluginYAML(r io.Reader) (*pluginInfo, error) {
+	info := &pluginInfo{}
+	var key string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "-") {
+			// Items of a block list, e.g. "authors:\n  - name".
+			if item := strings.TrimSpace(line); key == "authors" && strings.HasPrefix(item, "- ") {
+				info.Authors = append(info.Authors, yamlScalar(item[2:]))
+			}
+			continue
+		}
+		k, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(k)
+		value = strings.TrimSpace(value)
+		switch key {
+		case "name":
+			info.Name = yamlScalar(value)
+		case "version":
+			info.Version = yamlScalar(value)
+		case "author":
+			if value != "" {
+				info.Authors = append([]string{yamlScalar(value)}, info.Authors...)
+			}
+		case "authors":
+			// Flow lists, e.g. "authors: [a, b]".
+			if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+				for _, a := range strings.Split(value[1:len(value)-1], ",") {
+					if a = yamlScalar(a); a != "" {
+						info.Authors = append(info.Authors, a)
+					}
+				}
+			}
+		}
+	}
+	return info, s.Err()
+}
+
+// yamlScalar returns the value of a plain or quoted YAML scalar.
+func yamlScalar(s string) string {
+	return strings.Trim(strings.TrimSpace(s), `"'`)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newJar returns a jar containing the file name with the content data.
+func newJar(t *testing.T, name, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte(data))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParsePluginYAML(t *testing.T) {
+	tests := []struct {
+		in   string
+		want *pluginInfo
+	}{
+		{"name: Essentials\nversion: '2.20.1'\nmain: com.earth2me.essentials.Essentials\n",
+			&pluginInfo{Name: "Essentials", Version: "2.20.1"}},
+		{"name: \"Foo\"\nauthor: alice\nauthors: [bob, 'carol']\n",
+			&pluginInfo{Name: "Foo", Authors: []string{"alice", "bob", "carol"}}},
+		{"authors:\n  - bob\n  - \"carol\"\nauthor: alice\nname: Foo\n",
+			&pluginInfo{Name: "Foo", Authors: []string{"alice", "bob", "carol"}}},
+		{"name: Foo\ncommands:\n  foo:\n    aliases:\n    - f\nversion: 1.0\n",
+			&pluginInfo{Name: "Foo", Version: "1.0"}},
+		{"authors:\n- bob\n", &pluginInfo{Authors: []string{"bob"}}},
+		{"not yaml", &pluginInfo{}},
+	}
+	for _, tt := range tests {
+		got, err := parsePluginYAML(strings.NewReader(tt.in))
+		if err != nil {
+			t.Errorf("parsePluginYAML(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePluginYAML(%q) returned %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReadPluginInfo(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	info, err := readPluginInfo(write("a.jar", newJar(t, "paper-plugin.yml", "name: A\nversion: 1\n")))
+	if err != nil {
+		t.Fatalf("readPluginInfo returned error: %v", err)
+	}
+	if want := (&pluginInfo{Name: "A", Version: "1"}); !reflect.DeepEqual(info, want) {
+		t.Errorf("readPluginInfo returned %+v, want %+v", info, want)
+	}
+
+	if _, err := readPluginInfo(write("b.jar", newJar(t, "META-INF/MANIFEST.MF", ""))); err == nil {
+		t.Error("readPluginInfo of a jar without plugin.yml returned no error")
+	}
+	if _, err := readPluginInfo(write("c.jar", []byte("<html>"))); err == nil {
+		t.Error("readPluginInfo of a file which is not a jar returned no error")
+	}
+}
+
+func TestManifest(t *testing.T) {
+	m := &manifest{}
+	m.put(&installedPlugin{File: "b.jar", Resource: 2, Version: 1})
+	m.put(&installedPlugin{File: "a.jar", Resource: 1, Version: 1})
+	m.put(&installedPlugin{File: "c.jar", Resource: 2, Version: 2})
+
+	var files []string
+	for _, p := range m.Plugins {
+		files = append(files, p.File)
+	}
+	if want := []string{"a.jar", "c.jar"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("manifest has files %v, want %v", files, want)
+	}
+	if p := m.byResource(2); p == nil || p.Version != 2 {
+		t.Errorf("byResource(2) returned %+v, want version 2", p)
+	}
+	if p := m.byFile("b.jar"); p != nil {
+		t.Errorf("byFile of a replaced file returned %+v", p)
+	}
+
+	m.remove(1)
+	if p := m.byFile("a.jar"); p != nil || len(m.Plugins) != 1 {
+		t.Errorf("manifest after remove has plugins %+v", m.Plugins)
+	}
+}
+
+func TestPluginDir(t *testing.T) {
+	path := t.TempDir()
+	d, err := openPluginDir(path)
+	if err != nil {
+		t.Fatalf("openPluginDir returned error: %v", err)
+	}
+	if len(d.manifest.Plugins) != 0 {
+		t.Errorf("new plugins directory has plugins %+v", d.manifest.Plugins)
+	}
+
+	d.manifest.put(&installedPlugin{File: "a.jar", Resource: 1, Name: "A", Version: 3})
+	if err := d.save(); err != nil {
+		t.Fatalf("save returned error: %v", err)
+	}
+	reopened, err := openPluginDir(path)
+	if err != nil {
+		t.Fatalf("openPluginDir returned error: %v", err)
+	}
+	if !reflect.DeepEqual(reopened.manifest, d.manifest) {
+		t.Errorf("reopened manifest is %+v, want %+v", reopened.manifest, d.manifest)
+	}
+
+	for _, name := range []string{"a.jar", "B.JAR", "c.txt"} {
+		os.WriteFile(filepath.Join(path, name), []byte(name), 0644)
+	}
+	os.Mkdir(filepath.Join(path, "d.jar"), 0755)
+	jars, err := d.jars()
+	if err != nil {
+		t.Fatalf("jars returned error: %v", err)
+	}
+	if want := []string{"B.JAR", "a.jar"}; !reflect.DeepEqual(jars, want) {
+		t.Errorf("jars returned %v, want %v", jars, want)
+	}
+
+	// Backups of the same file never overwrite each other.
+	first, err := d.backup("a.jar")
+	if err != nil {
+		t.Fatalf("backup returned error: %v", err)
+	}
+	os.WriteFile(filepath.Join(path, "a.jar"), []byte("second"), 0644)
+	second, err := d.backup("a.jar")
+	if err != nil {
+		t.Fatalf("backup returned error: %v", err)
+	}
+	if first == second {
+		t.Errorf("backups share the path %s", first)
+	}
+	if d.exists("a.jar") {
+		t.Error("backed up jar still exists")
+	}
+	for path, want := range map[string]string{first: "a.jar", second: "second"} {
+		if got, _ := os.ReadFile(path); string(got) != want {
+			t.Errorf("backup %s contains %q, want %q", path, got, want)
+		}
+	}
+
+	var usageErr usageError
+	if _, err := openPluginDir(filepath.Join(path, "c.txt")); !errors.As(err, &usageErr) {
+		t.Errorf("openPluginDir of a file returned %v, want a usage error", err)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+// print writes v to the output as indented JSON if --json was given, and
+// otherwise as the table written by table.
+func (a *app) print(v interface{}, table func(w io.Writer)) error {
+	if a.json {
+		enc := json.NewEncoder(a.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// row writes the tab-separated columns followed by a newline.
+func row(w io.Writer, columns ...interface{}) {
+	for i, c := range columns {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, c)
+	}
+	fmt.Fprintln(w)
+}
+
+// date formats a Unix timestamp as used by the Spiget API.
+func date(unix int) string {
+	if unix == 0 {
+		return "-"
+	}
+	return time.Unix(int64(unix), 0).UTC().Format("2006-01-02")
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+func resourceTable(resources []*spiget.Resource) func(w io.Writer) {
+	return func(w io.Writer) {
+		row(w, "ID", "NAME", "AUTHOR", "DOWNLOADS", "RATING", "UPDATED")
+		for _, r := range resources {
+			row(w, r.ID, truncate(r.Name, 40), r.Author.ID, r.Downloads,
+				fmt.Sprintf("%.2f (%d)", r.Rating.Average, r.Rating.Count), date(r.UpdateDate))
+		}
+	}
+}
+
+func authorTable(authors []*spiget.Author) func(w io.Writer) {
+	return func(w io.Writer) {
+		row(w, "ID", "NAME")
+		for _, a := range authors {
+			row(w, a.ID, a.Name)
+		}
+	}
+}
+
+func categoryTable(categories []*spiget.Category) func(w io.Writer) {
+	return func(w io.Writer) {
+		row(w, "ID", "NAME")
+		for _, c := range categories {
+			row(w, c.ID, c.Name)
+		}
+	}
+}
+
+func versionTable(versions []*spiget.Version) func(w io.Writer) {
+	return func(w io.Writer) {
+		row(w, "ID", "NAME", "RELEASED", "DOWNLOADS")
+		for _, v := range versions {
+			row(w, v.ID, v.Name, date(v.ReleaseDate), v.Downloads)
+		}
+	}
+}
+
+func updateTable(updates []*spiget.Update) func(w io.Writer) {
+	return func(w io.Writer) {
+		row(w, "ID", "DATE", "LIKES", "TITLE")
+		for _, u := range updates {
+			row(w, u.ID, date(u.Date), u.Likes, truncate(u.Title, 60))
+		}
+	}
+}
+
+func reviewTable(reviews []*spiget.Review) func(w io.Writer) {
+	return func(w io.Writer) {
+		row(w, "ID", "DATE", "AUTHOR", "RATING", "VERSION")
+		for _, r := range reviews {
+			row(w, r.ID, date(r.Date), r.Author.ID, r.Rating.Average, r.Version)
+		}
+	}
+}
@@ -0,0 +1,588 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+// pluginsCmd holds the flags shared by the plugins subcommands.
+type pluginsCmd struct {
+	*app
+	dir       *pluginDir
+	dirPath   string
+	dryRun    bool
+	mcVersion string
+
+	// progress receives the progress messages. It is stderr if --json was
+	// given, so that stdout only holds the JSON output.
+	progress io.Writer
+}
+
+func (a *app) pluginsFlags(name string) (*pluginsCmd, *flag.FlagSet) {
+	c := &pluginsCmd{app: a}
+	fs := a.flagSet(name)
+	fs.StringVar(&c.dirPath, "dir", "plugins", "plugins directory of the server")
+	fs.BoolVar(&c.dryRun, "dry-run", false, "only print what would be done")
+	fs.StringVar(&c.mcVersion, "mc", "", "only consider resources tested with this Minecraft version, e.g. 1.20")
+	return c, fs
+}
+
+// parse parses the flags and opens the plugins directory.
+func (c *pluginsCmd) parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int, names string) ([]string, error) {
+	args, err := c.app.parse(fs, args, minArgs, maxArgs, names)
+	if err != nil {
+		return nil, err
+	}
+	c.progress = c.out
+	if c.json {
+		c.progress = c.errOut
+	}
+	c.dir, err = openPluginDir(c.dirPath)
+	return args, err
+}
+
+// pluginStatus is a row of the outdated report. For untracked jars, Resource
+// and Latest are set if the jar was identified by its plugin.yml.
+type pluginStatus struct {
+	File      string      `json:"file"`
+	Resource  int         `json:"resource,omitempty"`
+	Name      string      `json:"name"`
+	Installed string      `json:"installed"`
+	Latest    string      `json:"latest,omitempty"`
+	Status    string      `json:"status"`
+	Plugin    *pluginInfo `json:"plugin,omitempty"`
+
+	latest *spiget.Version
+}
+
+// Values of pluginStatus.Status.
+const (
+	statusUpToDate     = "up to date"
+	statusOutdated     = "outdated"
+	statusIncompatible = "incompatible"
+	statusUntracked    = "untracked"
+	statusError        = "error"
+)
+
+func pluginsOutdated(a *app, args []string) error {
+	c, fs := a.pluginsFlags("plugins outdated")
+	if _, err := c.parse(fs, args, 0, 0, "[--dir plugins] [--mc version]"); err != nil {
+		return err
+	}
+
+	statuses, err := c.check(nil)
+	if err != nil {
+		return err
+	}
+	return a.print(statuses, func(w io.Writer) {
+		row(w, "FILE", "RESOURCE", "INSTALLED", "LATEST", "STATUS")
+		for _, s := range statuses {
+			resource := "-"
+			if s.Resource > 0 {
+				resource = strconv.Itoa(s.Resource)
+			}
+			row(w, s.File, resource, s.Installed, s.Latest, s.Status)
+		}
+	})
+}
+
+// check compares the installed plugins with their latest versions on Spiget.
+// If ids is not empty, only the plugins of these resources are checked and
+// untracked jars are omitted.
+func (c *pluginsCmd) check(ids []int) ([]*pluginStatus, error) {
+	var statuses []*pluginStatus
+	tracked := c.dir.manifest.Plugins
+	if len(ids) > 0 {
+		tracked = nil
+		for _, id := range ids {
+			p := c.dir.manifest.byResource(id)
+			if p == nil {
+				return nil, fmt.Errorf("resource %d is not installed", id)
+			}
+			tracked = append(tracked, p)
+		}
+	}
+
+	resourceIDs := make([]int, len(tracked))
+	for i, p := range tracked {
+		resourceIDs[i] = p.Resource
+	}
+	resources, err := c.client.Resources.GetMany(c.ctx, resourceIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, p := range tracked {
+		s := &pluginStatus{File: p.File, Resource: p.Resource, Name: p.Name, Installed: versionLabel(p.VersionName, p.Version)}
+		statuses = append(statuses, s)
+
+		res := resources[i]
+		if res.Err != nil {
+			s.Status = statusError + ": " + res.Err.Error()
+			continue
+		}
+		latest, _, err := c.client.Resources.GetLatestVersion(c.ctx, p.Resource)
+		if err != nil {
+			s.Status = statusError + ": " + err.Error()
+			continue
+		}
+		s.latest = latest
+		s.Latest = versionLabel(latest.Name, latest.ID)
+
+		switch {
+		case latest.ID == p.Version:
+			s.Status = statusUpToDate
+		case !c.compatible(res.Resource):
+			s.Status = statusIncompatible
+		default:
+			s.Status = statusOutdated
+		}
+	}
+
+	if len(ids) > 0 {
+		return statuses, nil
+	}
+
+	jars, err := c.dir.jars()
+	if err != nil {
+		return nil, err
+	}
+	for _, jar := range jars {
+		if c.dir.manifest.byFile(jar) != nil {
+			continue
+		}
+		s := &pluginStatus{File: jar, Installed: "-", Status: statusUntracked}
+		if info, err := readPluginInfo(filepath.Join(c.dir.path, jar)); err == nil {
+			s.Plugin = info
+			s.Name = info.Name
+			s.Installed = info.Version
+			c.identify(s, info)
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// identify looks up the resource of an untracked jar and sets the resource
+// and its latest version on s.
+func (c *pluginsCmd) identify(s *pluginStatus, info *pluginInfo) {
+	match := c.resourceOf(info)
+	if match == nil {
+		return
+	}
+	s.Resource = match.ID
+	if latest, _, err := c.client.Resources.GetLatestVersion(c.ctx, match.ID); err == nil {
+		s.Latest = versionLabel(latest.Name, latest.ID)
+	}
+}
+
+// resourceOf looks up the resource of an untracked jar by the name and
+// authors declared in its plugin.yml. It returns nil if the lookup fails or
+// does not find exactly one resource.
+func (c *pluginsCmd) resourceOf(info *pluginInfo) *spiget.Resource {
+	if info.Name == "" {
+		return nil
+	}
+	opts := &spiget.ResourceSearchOptions{Field: spiget.SearchFieldName, ListOptions: spiget.ListOptions{Size: 100}}
+	found, _, err := c.client.Resources.Search(c.ctx, info.Name, opts)
+	if err != nil {
+		return nil
+	}
+
+	// Spiget only returns the author IDs of resources, so the authors are
+	// looked up by name. Unknown authors do not rule out a resource, as the
+	// plugin.yml may name a different account than SpigotMC.
+	authors := make(map[int]bool)
+	for _, name := range info.Authors {
+		candidates, _, err := c.client.Authors.Search(c.ctx, name, nil)
+		if err != nil {
+			continue
+		}
+		for _, a := range candidates {
+			if strings.EqualFold(a.Name, name) {
+				authors[a.ID] = true
+			}
+		}
+	}
+
+	var match *spiget.Resource
+	for _, r := range found {
+		if pluginKey(r.Name) != pluginKey(info.Name) || len(authors) > 0 && !authors[r.Author.ID] {
+			continue
+		}
+		if match != nil {
+			return nil
+		}
+		match = r
+	}
+	return match
+}
+
+// untrackedJar returns the jar not installed by spiget which is identified
+// as the resource by its plugin.yml, or "" if there is none.
+func (c *pluginsCmd) untrackedJar(r *spiget.Resource) (string, error) {
+	jars, err := c.dir.jars()
+	if err != nil {
+		return "", err
+	}
+	for _, jar := range jars {
+		if c.dir.manifest.byFile(jar) != nil {
+			continue
+		}
+		info, err := readPluginInfo(filepath.Join(c.dir.path, jar))
+		if err != nil || pluginKey(info.Name) != pluginKey(r.Name) {
+			continue
+		}
+		if match := c.resourceOf(info); match != nil && match.ID == r.ID {
+			return jar, nil
+		}
+	}
+	return "", nil
+}
+
+// pluginKey normalizes a resource or plugin name for comparison, ignoring
+// the tagline of resource names, case and separators.
+func pluginKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, baseName(name))
+}
+
+// compatible reports whether the resource was tested with the target
+// Minecraft version. Resources without tested versions are assumed to be
+// compatible, as are all resources if no target was given.
+func (c *pluginsCmd) compatible(r *spiget.Resource) bool {
+	if c.mcVersion == "" || len(r.TestedVersions) == 0 {
+		return true
+	}
+	for _, tested := range r.TestedVersions {
+		if tested == c.mcVersion || strings.HasPrefix(c.mcVersion, tested+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func pluginsInstall(a *app, args []string) error {
+	c, fs := a.pluginsFlags("plugins install")
+	args, err := c.parse(fs, args, 1, -1, "[--dir plugins] [--mc version] [--dry-run] <id>...")
+	if err != nil {
+		return err
+	}
+
+	sum := c.summary()
+	for _, arg := range args {
+		id, err := parseID(arg)
+		if err != nil {
+			return err
+		}
+		r, _, err := c.client.Resources.Get(c.ctx, id)
+		if err != nil {
+			sum.failed(strconv.Itoa(id), err)
+			continue
+		}
+		if !c.compatible(r) {
+			sum.skipped(r.Name, "not tested with Minecraft "+c.mcVersion)
+			continue
+		}
+		latest, _, err := c.client.Resources.GetLatestVersion(c.ctx, id)
+		if err != nil {
+			sum.failed(r.Name, err)
+			continue
+		}
+
+		// A jar of the resource installed without spiget is replaced,
+		// so that the server does not load two copies of the plugin.
+		file, note := jarName(r), ""
+		if p := c.dir.manifest.byResource(id); p != nil {
+			file = p.File
+		} else if jar, err := c.untrackedJar(r); err != nil {
+			sum.failed(r.Name, err)
+			continue
+		} else if jar != "" {
+			file, note = jar, ", replacing the untracked jar"
+		}
+		if err := c.install(r, latest, file); err != nil {
+			sum.failed(r.Name, err)
+			continue
+		}
+		sum.done(r.Name, "installed "+versionLabel(latest.Name, latest.ID)+" as "+file+note, c.dryRun)
+	}
+	return c.finish(sum, "installed")
+}
+
+func pluginsUpdate(a *app, args []string) error {
+	c, fs := a.pluginsFlags("plugins update")
+	args, err := c.parse(fs, args, 0, -1, "[--dir plugins] [--mc version] [--dry-run] [<id>...]")
+	if err != nil {
+		return err
+	}
+	ids := make([]int, len(args))
+	for i, arg := range args {
+		if ids[i], err = parseID(arg); err != nil {
+			return err
+		}
+	}
+
+	statuses, err := c.check(ids)
+	if err != nil {
+		return err
+	}
+
+	sum := c.summary()
+	for _, s := range statuses {
+		switch s.Status {
+		case statusUntracked, statusUpToDate:
+			continue
+		case statusIncompatible:
+			sum.skipped(s.Name, s.Latest+" not tested with Minecraft "+c.mcVersion)
+			continue
+		case statusOutdated:
+		default:
+			sum.failed(s.Name, errors.New(s.Status))
+			continue
+		}
+
+		r, _, err := c.client.Resources.Get(c.ctx, s.Resource)
+		if err != nil {
+			sum.failed(s.Name, err)
+			continue
+		}
+		if err := c.install(r, s.latest, s.File); err != nil {
+			sum.failed(s.Name, err)
+			continue
+		}
+		sum.done(s.Name, "updated "+s.Installed+" to "+s.Latest, c.dryRun)
+	}
+	return c.finish(sum, "updated")
+}
+
+func pluginsRemove(a *app, args []string) error {
+	c, fs := a.pluginsFlags("plugins remove")
+	args, err := c.parse(fs, args, 1, -1, "[--dir plugins] [--dry-run] <id|file>...")
+	if err != nil {
+		return err
+	}
+
+	sum := c.summary()
+	for _, arg := range args {
+		// A jar may be named like a resource ID, so file names take
+		// precedence.
+		p := c.dir.manifest.byFile(arg)
+		if id, err := strconv.Atoi(arg); err == nil && p == nil {
+			p = c.dir.manifest.byResource(id)
+		}
+		if p == nil {
+			sum.failed(arg, errors.New("not installed from Spiget"))
+			continue
+		}
+
+		if c.dryRun {
+			sum.done(p.Name, "removed "+p.File, true)
+			continue
+		}
+		if c.dir.exists(p.File) {
+			backup, err := c.dir.backup(p.File)
+			if err != nil {
+				sum.failed(p.Name, err)
+				continue
+			}
+			fmt.Fprintf(c.progress, "%s: backed up %s to %s\n", p.Name, p.File, backup)
+		}
+		c.dir.manifest.remove(p.Resource)
+		if err := c.dir.save(); err != nil {
+			return err
+		}
+		sum.done(p.Name, "removed "+p.File, false)
+	}
+	return c.finish(sum, "removed")
+}
+
+// install downloads the version of the resource as file, backing up the
+// replaced jar, and records it in the manifest.
+func (c *pluginsCmd) install(r *spiget.Resource, version *spiget.Version, file string) error {
+	switch {
+	case r.Premium:
+		return errors.New("premium resources cannot be downloaded")
+	case r.External:
+		return errors.New("externally hosted resources must be downloaded manually")
+	}
+	if c.dryRun {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(c.dir.path, "."+file+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	// The advertised file size is that of the latest version.
+	if version.ID == r.Version.ID {
+		_, err = c.client.Resources.DownloadVerifiedTo(c.ctx, r, tmp)
+	} else {
+		_, err = c.client.Resources.DownloadVersionTo(c.ctx, r.ID, version.ID, tmp)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkJar(tmp.Name()); err != nil {
+		return err
+	}
+
+	if c.dir.exists(file) {
+		backup, err := c.dir.backup(file)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.progress, "%s: backed up %s to %s\n", r.Name, file, backup)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir.path, file)); err != nil {
+		return err
+	}
+
+	c.dir.manifest.put(&installedPlugin{
+		File:        file,
+		Resource:    r.ID,
+		Name:        r.Name,
+		Version:     version.ID,
+		VersionName: version.Name,
+		Installed:   time.Now().UTC(),
+	})
+	return c.dir.save()
+}
+
+// checkJar verifies that the downloaded file is a jar and not e.g. an HTML
+// page served for a resource that cannot be downloaded directly.
+func checkJar(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(f, magic); err != nil || !bytes.Equal(magic, []byte("PK\x03\x04")) {
+		return errors.New("downloaded file is not a jar")
+	}
+	return nil
+}
+
+// baseName returns the resource name without its tagline, e.g. "Essentials"
+// for "Essentials | The essential plugin".
+func baseName(name string) string {
+	for _, sep := range []string{" | ", " - ", " [", " ("} {
+		if i := strings.Index(name, sep); i > 0 {
+			name = name[:i]
+		}
+	}
+	return name
+}
+
+// jarName returns the file name to install the resource as.
+func jarName(r *spiget.Resource) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case r == ' ':
+			return '-'
+		}
+		return -1
+	}, strings.TrimSpace(baseName(r.Name)))
+	if name == "" {
+		name = "resource-" + strconv.Itoa(r.ID)
+	}
+	return name + ".jar"
+}
+
+func versionLabel(name string, id int) string {
+	if name != "" {
+		return name
+	}
+	return "#" + strconv.Itoa(id)
+}
+
+// pluginResult is the outcome of a plugins command for one plugin.
+type pluginResult struct {
+	Name    string `json:"name"`
+	Result  string `json:"result"`
+	Message string `json:"message"`
+}
+
+// Values of pluginResult.Result.
+const (
+	resultOK      = "ok"
+	resultSkipped = "skipped"
+	resultFailed  = "failed"
+)
+
+// summary records the outcomes of a plugins command and writes them to the
+// progress output as they happen.
+type summary struct {
+	w              io.Writer
+	results        []*pluginResult
+	ok, skip, fail int
+}
+
+func (c *pluginsCmd) summary() *summary {
+	return &summary{w: c.progress, results: []*pluginResult{}}
+}
+
+func (s *summary) add(name, result, msg string) {
+	s.results = append(s.results, &pluginResult{Name: name, Result: result, Message: msg})
+}
+
+func (s *summary) done(name, msg string, dryRun bool) {
+	s.ok++
+	if dryRun {
+		msg = "would have " + msg
+	}
+	s.add(name, resultOK, msg)
+	fmt.Fprintf(s.w, "%s: %s\n", name, msg)
+}
+
+func (s *summary) skipped(name, reason string) {
+	s.skip++
+	s.add(name, resultSkipped, reason)
+	fmt.Fprintf(s.w, "%s: skipped, %s\n", name, reason)
+}
+
+func (s *summary) failed(name string, err error) {
+	s.fail++
+	s.add(name, resultFailed, err.Error())
+	fmt.Fprintf(s.w, "%s: failed: %v\n", name, err)
+}
+
+// finish writes the totals, and the results as JSON if --json was given. It
+// returns an error if any plugin failed.
+func (c *pluginsCmd) finish(s *summary, verb string) error {
+	fmt.Fprintf(s.w, "\n%d %s, %d skipped, %d failed\n", s.ok, verb, s.skip, s.fail)
+	if c.json {
+		if err := c.print(s.results, nil); err != nil {
+			return err
+		}
+	}
+	if s.fail > 0 {
+		return fmt.Errorf("%d plugins failed", s.fail)
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+func TestJarName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Essentials | The essential plugin", "Essentials.jar"},
+		{"World Edit - Map editor", "World-Edit.jar"},
+		{"LuckPerms [1.8-1.21]", "LuckPerms.jar"},
+		{"Vault (Economy API)", "Vault.jar"},
+		{"../evil/plugin", "..evilplugin.jar"},
+		{"★★★", "resource-7.jar"},
+	}
+	for _, tt := range tests {
+		if got := jarName(&spiget.Resource{ID: 7, Name: tt.name}); got != tt.want {
+			t.Errorf("jarName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPluginKey(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"World Edit | Map editor", "WorldEdit", true},
+		{"luck-perms", "LuckPerms", true},
+		{"Essentials_X", "EssentialsX", true},
+		{"Essentials", "EssentialsX", false},
+	}
+	for _, tt := range tests {
+		if got := pluginKey(tt.a) == pluginKey(tt.b); got != tt.want {
+			t.Errorf("pluginKey(%q) == pluginKey(%q) is %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestVersionLabel(t *testing.T) {
+	if got := versionLabel("1.2.3", 42); got != "1.2.3" {
+		t.Errorf("versionLabel(1.2.3, 42) = %q, want 1.2.3", got)
+	}
+	if got := versionLabel("", 42); got != "#42" {
+		t.Errorf("versionLabel(\"\", 42) = %q, want #42", got)
+	}
+}
+
+// fakePlugin is a resource served by fakeSpiget. Its latest version may be
+// changed between requests.
+type fakePlugin struct {
+	resource spiget.Resource
+	author   string
+	version  string // name of the latest version
+	jar      []byte
+}
+
+// setVersion makes v the latest version of the plugin.
+func (p *fakePlugin) setVersion(t *testing.T, id int, name string) {
+	p.resource.Version.ID = id
+	p.version = name
+	p.jar = newJar(t, "plugin.yml", "name: "+p.resource.Name+"\nversion: "+name+"\nauthor: "+p.author+"\n")
+}
+
+func newFakePlugin(t *testing.T, id int, name, author string) *fakePlugin {
+	p := &fakePlugin{resource: spiget.Resource{ID: id, Name: name, Author: spiget.Author{ID: id * 10}}, author: author}
+	p.setVersion(t, id*100, "1.0")
+	return p
+}
+
+// fakeSpiget serves the resources, latest versions, downloads and searches
+// of the plugins.
+func fakeSpiget(t *testing.T, plugins ...*fakePlugin) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reply := func(v interface{}) {
+			json.NewEncoder(w).Encode(v)
+		}
+		path := strings.Split(strings.TrimPrefix(r.URL.Path, "/v2/"), "/")
+		if path[0] == "search" && len(path) == 3 {
+			found := []interface{}{}
+			for _, p := range plugins {
+				switch {
+				case path[1] == "resources" && strings.EqualFold(p.resource.Name, path[2]):
+					found = append(found, p.resource)
+				case path[1] == "authors" && strings.EqualFold(p.author, path[2]):
+					found = append(found, spiget.Author{ID: p.resource.Author.ID, Name: p.author})
+				}
+			}
+			reply(found)
+			return
+		}
+
+		var p *fakePlugin
+		if len(path) >= 2 && path[0] == "resources" {
+			for _, candidate := range plugins {
+				if strconv.Itoa(candidate.resource.ID) == path[1] {
+					p = candidate
+				}
+			}
+		}
+		switch {
+		case p == nil:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "resource not found"}`))
+		case len(path) == 2:
+			reply(p.resource)
+		case len(path) == 4 && path[2] == "versions" && path[3] == "latest":
+			reply(spiget.Version{ID: p.resource.Version.ID, Name: p.version})
+		case path[len(path)-1] == "download":
+			w.Write(p.jar)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// runPlugins runs a plugins subcommand against the server on the plugins
+// directory dir and returns the exit code and output.
+func runPlugins(t *testing.T, server *httptest.Server, dir string, args ...string) (int, string) {
+	t.Helper()
+	var out bytes.Buffer
+	args = append([]string{"--base-url", server.URL + "/v2/", "plugins"}, append(args, "--dir", dir)...)
+	code := run(args, &out, &out)
+	return code, out.String()
+}
+
+// readManifest returns the plugins recorded in the manifest of dir.
+func readManifest(t *testing.T, dir string) []*installedPlugin {
+	t.Helper()
+	d, err := openPluginDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d.manifest.Plugins
+}
+
+// backups returns the contents of the backed up jars of dir.
+func backups(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, _ := os.ReadDir(filepath.Join(dir, backupDirName))
+	var contents []string
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, backupDirName, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, string(data))
+	}
+	return contents
+}
+
+func checkFile(t *testing.T, path string, want []byte) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("reading %s: %v", filepath.Base(path), err)
+		return
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s has unexpected content", filepath.Base(path))
+	}
+}
+
+func TestPluginsInstall(t *testing.T) {
+	foo := newFakePlugin(t, 1, "Foo | Does foo", "alice")
+	server := fakeSpiget(t, foo)
+	dir := t.TempDir()
+
+	if code, out := runPlugins(t, server, dir, "install", "1"); code != exitOK {
+		t.Fatalf("plugins install exited with %d: %s", code, out)
+	}
+	checkFile(t, filepath.Join(dir, "Foo.jar"), foo.jar)
+	plugins := readManifest(t, dir)
+	if len(plugins) != 1 || plugins[0].File != "Foo.jar" || plugins[0].Resource != 1 || plugins[0].Version != 100 || plugins[0].VersionName != "1.0" {
+		t.Errorf("manifest has plugins %+v, want Foo.jar of resource 1 version 100", plugins)
+	}
+
+	if code, out := runPlugins(t, server, dir, "install", "2"); code != exitError || !strings.Contains(out, "1 failed") {
+		t.Errorf("plugins install of a missing resource exited with %d: %s", code, out)
+	}
+}
+
+func TestPluginsInstallReplacesUntracked(t *testing.T) {
+	foo := newFakePlugin(t, 1, "Foo", "alice")
+	server := fakeSpiget(t, foo)
+	dir := t.TempDir()
+
+	old := newJar(t, "plugin.yml", "name: foo\nversion: 0.9\nauthors: [alice]\n")
+	other := newJar(t, "plugin.yml", "name: Bar\nversion: 1.0\n")
+	os.WriteFile(filepath.Join(dir, "foo-0.9.jar"), old, 0644)
+	os.WriteFile(filepath.Join(dir, "Bar.jar"), other, 0644)
+
+	code, out := runPlugins(t, server, dir, "install", "1")
+	if code != exitOK || !strings.Contains(out, "replacing the untracked jar") {
+		t.Fatalf("plugins install exited with %d: %s", code, out)
+	}
+	checkFile(t, filepath.Join(dir, "foo-0.9.jar"), foo.jar)
+	checkFile(t, filepath.Join(dir, "Bar.jar"), other)
+	if _, err := os.Stat(filepath.Join(dir, "Foo.jar")); err == nil {
+		t.Error("plugins install created a second jar of the plugin")
+	}
+	if got := backups(t, dir); len(got) != 1 || got[0] != string(old) {
+		t.Errorf("backed up %d jars, want the untracked jar", len(got))
+	}
+	if plugins := readManifest(t, dir); len(plugins) != 1 || plugins[0].File != "foo-0.9.jar" {
+		t.Errorf("manifest has plugins %+v, want foo-0.9.jar", plugins)
+	}
+}
+
+func TestPluginsUpdate(t *testing.T) {
+	foo := newFakePlugin(t, 1, "Foo", "alice")
+	server := fakeSpiget(t, foo)
+	dir := t.TempDir()
+
+	if code, out := runPlugins(t, server, dir, "install", "1"); code != exitOK {
+		t.Fatalf("plugins install exited with %d: %s", code, out)
+	}
+	old := foo.jar
+	foo.setVersion(t, 101, "1.1")
+
+	code, out := runPlugins(t, server, dir, "update")
+	if code != exitOK || !strings.Contains(out, "updated 1.0 to 1.1") {
+		t.Fatalf("plugins update exited with %d: %s", code, out)
+	}
+	checkFile(t, filepath.Join(dir, "Foo.jar"), foo.jar)
+	if got := backups(t, dir); len(got) != 1 || got[0] != string(old) {
+		t.Errorf("backed up %d jars, want the replaced version", len(got))
+	}
+	if plugins := readManifest(t, dir); len(plugins) != 1 || plugins[0].Version != 101 {
+		t.Errorf("manifest has plugins %+v, want version 101", plugins)
+	}
+
+	if code, out := runPlugins(t, server, dir, "update"); code != exitOK || !strings.Contains(out, "0 updated") {
+		t.Errorf("plugins update of up to date plugins exited with %d: %s", code, out)
+	}
+}
+
+func TestPluginsRemove(t *testing.T) {
+	foo := newFakePlugin(t, 1, "Foo", "alice")
+	server := fakeSpiget(t, foo)
+	dir := t.TempDir()
+
+	if code, out := runPlugins(t, server, dir, "install", "1"); code != exitOK {
+		t.Fatalf("plugins install exited with %d: %s", code, out)
+	}
+	if code, out := runPlugins(t, server, dir, "remove", "1"); code != exitOK {
+		t.Fatalf("plugins remove exited with %d: %s", code, out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Foo.jar")); err == nil {
+		t.Error("plugins remove left the jar")
+	}
+	if got := backups(t, dir); len(got) != 1 || got[0] != string(foo.jar) {
+		t.Errorf("backed up %d jars, want the removed jar", len(got))
+	}
+	if plugins := readManifest(t, dir); len(plugins) != 0 {
+		t.Errorf("manifest has plugins %+v, want none", plugins)
+	}
+
+	if code, out := runPlugins(t, server, dir, "remove", "Foo.jar"); code != exitError {
+		t.Errorf("plugins remove of a removed plugin exited with %d: %s", code, out)
+	}
+}
+
+func TestPluginsDryRun(t *testing.T) {
+	foo := newFakePlugin(t, 1, "Foo", "alice")
+	server := fakeSpiget(t, foo)
+	dir := t.TempDir()
+
+	code, out := runPlugins(t, server, dir, "install", "--dry-run", "1")
+	if code != exitOK || !strings.Contains(out, "would have installed 1.0 as Foo.jar") {
+		t.Fatalf("plugins install --dry-run exited with %d: %s", code, out)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("plugins install --dry-run created %d files", len(entries))
+	}
+
+	if code, out := runPlugins(t, server, dir, "install", "1"); code != exitOK {
+		t.Fatalf("plugins install exited with %d: %s", code, out)
+	}
+	installed := foo.jar
+	foo.setVersion(t, 101, "1.1")
+	for _, args := range [][]string{{"update", "--dry-run"}, {"remove", "--dry-run", "1"}} {
+		if code, out := runPlugins(t, server, dir, args...); code != exitOK || !strings.Contains(out, "would have") {
+			t.Errorf("plugins %s exited with %d: %s", strings.Join(args, " "), code, out)
+		}
+	}
+	if plugins := readManifest(t, dir); len(plugins) != 1 || plugins[0].Version != 100 {
+		t.Errorf("manifest has plugins %+v after dry runs, want version 100", plugins)
+	}
+	if got := backups(t, dir); len(got) != 0 {
+		t.Errorf("dry runs backed up %d jars", len(got))
+	}
+	checkFile(t, filepath.Join(dir, "Foo.jar"), installed)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// parseID parses a positional resource, author or category ID.
+func parseID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		return 0, usagef("invalid ID %q", s)
+	}
+	return id, nil
+}
+
+func resourceGet(a *app, args []string) error {
+	fs := a.flagSet("resource get")
+	args, err := a.parse(fs, args, 1, 1, "<id>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	r, _, err := a.client.Resources.Get(a.ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.print(r, func(w io.Writer) {
+		row(w, "ID", r.ID)
+		row(w, "Name", r.Name)
+		row(w, "Tag", r.Tag)
+		row(w, "Author", r.Author.ID)
+		row(w, "Category", r.Category.ID)
+		row(w, "Downloads", r.Downloads)
+		row(w, "Likes", r.Likes)
+		row(w, "Rating", fmt.Sprintf("%.2f (%d)", r.Rating.Average, r.Rating.Count))
+		row(w, "Released", date(r.ReleaseDate))
+		row(w, "Updated", date(r.UpdateDate))
+		row(w, "Tested versions", strings.Join(r.TestedVersions, ", "))
+		row(w, "Premium", r.Premium)
+		row(w, "External", r.External)
+		if r.SourceCodeLink != "" {
+			row(w, "Source code", r.SourceCodeLink)
+		}
+	})
+}
+
+func resourceVersions(a *app, args []string) error {
+	fs := a.flagSet("resource versions")
+	listOpts := listFlags(fs)
+	args, err := a.parse(fs, args, 1, 1, "<id>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	versions, _, err := a.client.Resources.GetVersions(a.ctx, id, listOpts())
+	if err != nil {
+		return err
+	}
+	return a.print(versions, versionTable(versions))
+}
+
+func resourceUpdates(a *app, args []string) error {
+	fs := a.flagSet("resource updates")
+	listOpts := listFlags(fs)
+	args, err := a.parse(fs, args, 1, 1, "<id>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	updates, _, err := a.client.Resources.GetUpdates(a.ctx, id, listOpts())
+	if err != nil {
+		return err
+	}
+	return a.print(updates, updateTable(updates))
+}
+
+func resourceReviews(a *app, args []string) error {
+	fs := a.flagSet("resource reviews")
+	listOpts := listFlags(fs)
+	args, err := a.parse(fs, args, 1, 1, "<id>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	reviews, _, err := a.client.Resources.GetReviews(a.ctx, id, listOpts())
+	if err != nil {
+		return err
+	}
+	return a.print(reviews, reviewTable(reviews))
+}
+
+func resourceDownload(a *app, args []string) error {
+	fs := a.flagSet("resource download")
+	output := fs.String("o", "", "output file (default <id>.jar, - for stdout)")
+	version := fs.Int("version", 0, "ID of the version to download (default latest)")
+	args, err := a.parse(fs, args, 1, 1, "[-o file] [--version id] <id>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if *output == "-" {
+		return a.download(id, *version, a.out)
+	}
+
+	name := *output
+	if name == "" {
+		name = strconv.Itoa(id) + ".jar"
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := a.download(id, *version, f); err != nil {
+		f.Close()
+		os.Remove(name)
+		return err
+	}
+	return f.Close()
+}
+
+// download writes the given version of a resource to w, or the latest
+// version if version is zero.
+func (a *app) download(id, version int, w io.Writer) error {
+	var err error
+	if version > 0 {
+		_, err = a.client.Resources.DownloadVersionTo(a.ctx, id, version, w)
+	} else {
+		_, err = a.client.Resources.DownloadTo(a.ctx, id, w)
+	}
+	return err
+}
@@ -0,0 +1,30 @@
+package main
+
+import "github.com/sunxyw/go-spiget/spiget"
+
+func search(a *app, args []string) error {
+	fs := a.flagSet("search")
+	authors := fs.Bool("authors", false, "search authors instead of resources")
+	field := fs.String("field", "", "field to search in (name, tag or author)")
+	listOpts := listFlags(fs)
+	args, err := a.parse(fs, args, 1, 1, "[--authors] [--field name|tag|author] <query>")
+	if err != nil {
+		return err
+	}
+
+	if *authors {
+		opts := &spiget.AuthorSearchOptions{Field: spiget.SearchField(*field), ListOptions: listOpts()}
+		found, _, err := a.client.Authors.Search(a.ctx, args[0], opts)
+		if err != nil {
+			return err
+		}
+		return a.print(found, authorTable(found))
+	}
+
+	opts := &spiget.ResourceSearchOptions{Field: spiget.SearchField(*field), ListOptions: listOpts()}
+	found, _, err := a.client.Resources.Search(a.ctx, args[0], opts)
+	if err != nil {
+		return err
+	}
+	return a.print(found, resourceTable(found))
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+func status(a *app, args []string) error {
+	fs := a.flagSet("status")
+	if _, err := a.parse(fs, args, 0, 0, ""); err != nil {
+		return err
+	}
+
+	s, _, err := a.client.Status.Get(a.ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(s, func(w io.Writer) {
+		if st := s.Status; st != nil {
+			row(w, "Server", st.Server.Name+" ("+st.Server.Mode+")")
+			row(w, "Fetch active", st.Fetch.Active)
+			row(w, "Fetch page", fmt.Sprintf("%d/%d", st.Fetch.Page.Index, st.Fetch.Page.Amount))
+		}
+		if st := s.Stats; st != nil {
+			row(w, "Resources", st.Resources)
+			row(w, "Authors", st.Authors)
+			row(w, "Categories", st.Categories)
+			row(w, "Updates", st.ResourceUpdates)
+			row(w, "Versions", st.ResourceVersions)
+			row(w, "Reviews", st.Reviews)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io"
+
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+func webhookRegister(a *app, args []string) error {
+	fs := a.flagSet("webhook register")
+	args, err := a.parse(fs, args, 2, -1, "<url> <event>...")
+	if err != nil {
+		return err
+	}
+
+	webhook, _, err := a.client.Webhook.Register(a.ctx, args[0], args[1:])
+	if err != nil {
+		return err
+	}
+	return a.print(webhook, func(w io.Writer) {
+		row(w, "ID", webhook.ID)
+		row(w, "Secret", webhook.Secret)
+	})
+}
+
+func webhookDelete(a *app, args []string) error {
+	fs := a.flagSet("webhook delete")
+	args, err := a.parse(fs, args, 2, 2, "<id> <secret>")
+	if err != nil {
+		return err
+	}
+
+	_, err = a.client.Webhook.Delete(a.ctx, spiget.Webhook{ID: args[0], Secret: args[1]})
+	return err
+}
+
+func webhookStatus(a *app, args []string) error {
+	fs := a.flagSet("webhook status")
+	args, err := a.parse(fs, args, 1, 1, "<id>")
+	if err != nil {
+		return err
+	}
+
+	status, _, err := a.client.Webhook.GetStatus(a.ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return a.print(status, func(w io.Writer) {
+		row(w, "Status", status.Status)
+		row(w, "Failed connections", status.FailedConnections)
+	})
+}
+
+func webhookEvents(a *app, args []string) error {
+	fs := a.flagSet("webhook events")
+	if _, err := a.parse(fs, args, 0, 0, ""); err != nil {
+		return err
+	}
+
+	events, _, err := a.client.Webhook.GetEvents(a.ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(events, func(w io.Writer) {
+		for _, e := range events.Events {
+			row(w, e)
+		}
+	})
+}
@@ -11,7 +11,7 @@ func SearchResources() {
 	client := spiget.NewClient(nil)
 
 	opt := &spiget.ResourceSearchOptions{
-		Field: "name",
+		Field: spiget.SearchFieldName,
 	}
 	resources, _, err := client.Resources.Search(context.Background(), "PlaceholderAPI", opt)
 	if err != nil {
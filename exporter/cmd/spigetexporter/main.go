@@ -0,0 +1,68 @@
+// Command spigetexporter serves Prometheus metrics about the status of
+// Spiget and the statistics of watched resources.
+//
+// Usage:
+//
+//	spigetexporter [flags] [<resource id>...]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sunxyw/go-spiget/exporter"
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+func main() {
+	var (
+		listen   = flag.String("listen", ":9877", "address to serve metrics on")
+		path     = flag.String("path", "/metrics", "path to serve metrics on")
+		baseURL  = flag.String("base-url", "", "base URL of the Spiget API (default \"https://api.spiget.org/v2/\")")
+		cacheTTL = flag.Duration("cache-ttl", time.Minute, "minimum time between refreshes from the API")
+		timeout  = flag.Duration("timeout", 30*time.Second, "timeout of a refresh")
+		workers  = flag.Int("workers", 4, "number of resources fetched concurrently")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: spigetexporter [flags] [<resource id>...]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var resources []int
+	for _, arg := range flag.Args() {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			fmt.Fprintf(os.Stderr, "spigetexporter: invalid resource ID %q\n", arg)
+			os.Exit(2)
+		}
+		resources = append(resources, id)
+	}
+
+	client := spiget.NewClient(nil)
+	if *baseURL != "" {
+		var err error
+		if client, err = spiget.NewCustomClient(*baseURL, nil); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(spigetexporter.NewCollector(client, spigetexporter.Config{
+		Resources: resources,
+		CacheTTL:  *cacheTTL,
+		Timeout:   *timeout,
+		Workers:   *workers,
+	}))
+
+	http.Handle(*path, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	log.Printf("serving metrics on %s%s", *listen, *path)
+	log.Fatal(http.ListenAndServe(*listen, nil))
+}
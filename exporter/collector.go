@@ -0,0 +1,288 @@
+// Package spigetexporter exposes the status of Spiget and the statistics of
+// watched resources as Prometheus metrics.
+//
+// The Spiget API is only queried when metrics are scraped, and at most once
+// per Config.CacheTTL; scrapes in between are served from the cache.
+package spigetexporter
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+const namespace = "spiget"
+
+// Config configures a Collector.
+type Config struct {
+	// Resources are the IDs of the resources to export statistics for.
+	// Duplicates are ignored.
+	Resources []int
+
+	// CacheTTL is the minimum time between two refreshes from the API.
+	// Defaults to one minute.
+	CacheTTL time.Duration
+
+	// Timeout limits the duration of a refresh. Defaults to 30 seconds.
+	Timeout time.Duration
+
+	// Workers is the number of resources fetched concurrently. Defaults to
+	// the default of spiget.BatchOptions.
+	Workers int
+}
+
+var (
+	upDesc = prometheus.NewDesc(namespace+"_up",
+		"Whether the last refresh from the Spiget API succeeded.", nil, nil)
+	lastRefreshDesc = prometheus.NewDesc(namespace+"_last_refresh_timestamp_seconds",
+		"Time of the last refresh from the Spiget API.", nil, nil)
+	refreshDurationDesc = prometheus.NewDesc(namespace+"_refresh_duration_seconds",
+		"Duration of the last refresh from the Spiget API.", nil, nil)
+
+	fetchActiveDesc = prometheus.NewDesc(namespace+"_fetch_active",
+		"Whether the fetch is running.", []string{"fetch"}, nil)
+	fetchStartDesc = prometheus.NewDesc(namespace+"_fetch_start_timestamp_seconds",
+		"Start time of the current or last fetch.", []string{"fetch"}, nil)
+	fetchEndDesc = prometheus.NewDesc(namespace+"_fetch_end_timestamp_seconds",
+		"End time of the last completed fetch.", []string{"fetch"}, nil)
+	fetchPageDesc = prometheus.NewDesc(namespace+"_fetch_page_index",
+		"Page the resource fetch is at.", nil, nil)
+	fetchPagesDesc = prometheus.NewDesc(namespace+"_fetch_page_amount",
+		"Number of pages of the resource fetch.", nil, nil)
+	fetchItemDesc = prometheus.NewDesc(namespace+"_fetch_item_index",
+		"Item of the current page the resource fetch is at.", nil, nil)
+	existenceIndexDesc = prometheus.NewDesc(namespace+"_existence_document_index",
+		"Document the existence check is at.", nil, nil)
+	existenceAmountDesc = prometheus.NewDesc(namespace+"_existence_document_amount",
+		"Number of documents of the existence check.", nil, nil)
+
+	statsDesc = prometheus.NewDesc(namespace+"_stats",
+		"Number of entities known to Spiget.", []string{"type"}, nil)
+
+	resourceInfoDesc = prometheus.NewDesc(namespace+"_resource_info",
+		"Name of the resource. Always 1.", []string{"resource", "name"}, nil)
+
+	resourceLabels        = []string{"resource"}
+	resourceDownloadsDesc = prometheus.NewDesc(namespace+"_resource_downloads_total",
+		"Downloads of the resource.", resourceLabels, nil)
+	resourceLikesDesc = prometheus.NewDesc(namespace+"_resource_likes",
+		"Likes of the resource.", resourceLabels, nil)
+	resourceRatingDesc = prometheus.NewDesc(namespace+"_resource_rating_average",
+		"Average rating of the resource.", resourceLabels, nil)
+	resourceRatingsDesc = prometheus.NewDesc(namespace+"_resource_rating_count",
+		"Number of ratings of the resource.", resourceLabels, nil)
+	resourceVersionAgeDesc = prometheus.NewDesc(namespace+"_resource_latest_version_age_seconds",
+		"Time since the latest version of the resource was released.", resourceLabels, nil)
+)
+
+// snapshot is the data of a refresh.
+type snapshot struct {
+	status    *spiget.StatusResponse
+	resources []*spiget.Resource
+
+	// versions holds the latest versions by resource ID.
+	versions map[int]*spiget.Version
+}
+
+// Collector is a prometheus.Collector exporting Spiget metrics. It is safe
+// for concurrent use; concurrent scrapes share a single refresh.
+type Collector struct {
+	client *spiget.Client
+	config Config
+
+	refreshErrors prometheus.Counter
+
+	mu          sync.Mutex
+	data        snapshot
+	up          bool
+	lastRefresh time.Time
+	duration    time.Duration
+}
+
+// NewCollector returns a Collector querying the API with client.
+func NewCollector(client *spiget.Client, config Config) *Collector {
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = time.Minute
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	// Duplicate IDs would be exported as duplicate series, failing scrapes.
+	seen := make(map[int]bool, len(config.Resources))
+	var resources []int
+	for _, id := range config.Resources {
+		if !seen[id] {
+			seen[id] = true
+			resources = append(resources, id)
+		}
+	}
+	config.Resources = resources
+
+	return &Collector{
+		client: client,
+		config: config,
+		data:   snapshot{versions: make(map[int]*spiget.Version)},
+		refreshErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "refresh_errors_total",
+			Help:      "Number of failed refreshes from the Spiget API.",
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		upDesc, lastRefreshDesc, refreshDurationDesc,
+		fetchActiveDesc, fetchStartDesc, fetchEndDesc,
+		fetchPageDesc, fetchPagesDesc, fetchItemDesc,
+		existenceIndexDesc, existenceAmountDesc, statsDesc,
+		resourceInfoDesc, resourceDownloadsDesc, resourceLikesDesc,
+		resourceRatingDesc, resourceRatingsDesc, resourceVersionAgeDesc,
+	} {
+		ch <- d
+	}
+	c.refreshErrors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector. It refreshes the data from the
+// API if the cache has expired. If the refresh fails, the last successfully
+// fetched data is exported and spiget_up is 0.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.lastRefresh) >= c.config.CacheTTL {
+		ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+		c.refresh(ctx)
+		cancel()
+	}
+
+	gauge := func(desc *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, labels...)
+	}
+
+	gauge(upDesc, boolValue(c.up))
+	gauge(lastRefreshDesc, float64(c.lastRefresh.Unix()))
+	gauge(refreshDurationDesc, c.duration.Seconds())
+	c.refreshErrors.Collect(ch)
+
+	if sr := c.data.status; sr != nil {
+		if s := sr.Status; s != nil {
+			fetches := []struct {
+				name       string
+				active     bool
+				start, end int64
+			}{
+				{"fetch", s.Fetch.Active, s.Fetch.Start, s.Fetch.End},
+				{"rest_fetch", s.RestFetch.Active, s.RestFetch.Start, s.RestFetch.End},
+				{"existence", s.Existence.Active, s.Existence.Start, s.Existence.End},
+			}
+			for _, f := range fetches {
+				gauge(fetchActiveDesc, boolValue(f.active), f.name)
+				gauge(fetchStartDesc, millis(f.start), f.name)
+				gauge(fetchEndDesc, millis(f.end), f.name)
+			}
+			gauge(fetchPageDesc, float64(s.Fetch.Page.Index))
+			gauge(fetchPagesDesc, float64(s.Fetch.Page.Amount))
+			gauge(fetchItemDesc, float64(s.Fetch.Page.Item.Index))
+			gauge(existenceIndexDesc, float64(s.Existence.Document.Index))
+			gauge(existenceAmountDesc, float64(s.Existence.Document.Amount))
+		}
+		if s := sr.Stats; s != nil {
+			gauge(statsDesc, float64(s.Resources), "resources")
+			gauge(statsDesc, float64(s.Authors), "authors")
+			gauge(statsDesc, float64(s.Categories), "categories")
+			gauge(statsDesc, float64(s.ResourceUpdates), "resource_updates")
+			gauge(statsDesc, float64(s.ResourceVersions), "resource_versions")
+			gauge(statsDesc, float64(s.Reviews), "reviews")
+		}
+	}
+
+	now := time.Now()
+	for _, r := range c.data.resources {
+		labels := []string{strconv.Itoa(r.ID)}
+		gauge(resourceInfoDesc, 1, strconv.Itoa(r.ID), r.Name)
+		ch <- prometheus.MustNewConstMetric(resourceDownloadsDesc, prometheus.CounterValue, float64(r.Downloads), labels...)
+		gauge(resourceLikesDesc, float64(r.Likes), labels...)
+		gauge(resourceRatingDesc, r.Rating.Average, labels...)
+		gauge(resourceRatingsDesc, float64(r.Rating.Count), labels...)
+		if v := c.data.versions[r.ID]; v != nil && v.ReleaseDate > 0 {
+			released := time.Unix(int64(v.ReleaseDate), 0)
+			gauge(resourceVersionAgeDesc, now.Sub(released).Seconds(), labels...)
+		}
+	}
+}
+
+// refresh fetches the status and the watched resources. Data that could not
+// be fetched is kept from the previous refresh.
+func (c *Collector) refresh(ctx context.Context) {
+	start := time.Now()
+	ok := true
+
+	if status, _, err := c.client.Status.Get(ctx); err == nil {
+		c.data.status = status
+	} else {
+		ok = false
+	}
+
+	if len(c.config.Resources) > 0 {
+		results, err := c.client.Resources.GetMany(ctx, c.config.Resources, &spiget.BatchOptions{Workers: c.config.Workers})
+		if err != nil {
+			ok = false
+		}
+
+		previous := make(map[int]*spiget.Resource, len(c.data.resources))
+		for _, r := range c.data.resources {
+			previous[r.ID] = r
+		}
+		resources := make([]*spiget.Resource, 0, len(results))
+		for _, res := range results {
+			r := res.Resource
+			if res.Err != nil {
+				ok = false
+				if r = previous[res.ID]; r == nil {
+					continue
+				}
+			}
+			resources = append(resources, r)
+
+			// The latest version is only fetched again when it changed.
+			if v := c.data.versions[r.ID]; v != nil && v.ID == r.Version.ID {
+				continue
+			}
+			v, _, err := c.client.Resources.GetLatestVersion(ctx, r.ID)
+			if err != nil {
+				ok = false
+				continue
+			}
+			c.data.versions[r.ID] = v
+		}
+		c.data.resources = resources
+	}
+
+	if !ok {
+		c.refreshErrors.Inc()
+	}
+	c.up = ok
+	c.lastRefresh = time.Now()
+	c.duration = time.Since(start)
+}
+
+// millis converts a timestamp in milliseconds, as used by the status
+// endpoint, to seconds.
+func millis(ms int64) float64 {
+	return float64(ms) / 1000
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
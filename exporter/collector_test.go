@@ -0,0 +1,60 @@
+package spigetexporter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+func TestCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status":
+			fmt.Fprint(w, `{"status": {}, "stats": {"resources": 5}}`)
+		case "/resources/1":
+			fmt.Fprint(w, `{"id": 1, "name": "One", "downloads": 10, "version": {"id": 7}}`)
+		case "/resources/2":
+			fmt.Fprint(w, `{"id": 2, "name": "Two", "downloads": 20, "version": {"id": 8}}`)
+		case "/resources/1/versions/latest", "/resources/2/versions/latest":
+			fmt.Fprint(w, `{"id": 7, "releaseDate": 1000}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := spiget.New(spiget.WithBaseURL(server.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	collector := NewCollector(client, Config{Resources: []int{1, 2, 1}})
+
+	// A pedantic registry fails the scrape on duplicate series.
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+
+	want := `
+# HELP spiget_resource_downloads_total Downloads of the resource.
+# TYPE spiget_resource_downloads_total counter
+spiget_resource_downloads_total{resource="1"} 10
+spiget_resource_downloads_total{resource="2"} 20
+# HELP spiget_resource_info Name of the resource. Always 1.
+# TYPE spiget_resource_info gauge
+spiget_resource_info{name="One",resource="1"} 1
+spiget_resource_info{name="Two",resource="2"} 1
+# HELP spiget_up Whether the last refresh from the Spiget API succeeded.
+# TYPE spiget_up gauge
+spiget_up 1
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(want),
+		"spiget_resource_downloads_total", "spiget_resource_info", "spiget_up")
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,221 @@
+// Package spigetotel instruments a spiget.Client with OpenTelemetry tracing
+// and metrics. It is a separate module, so the core client does not depend on
+// OpenTelemetry.
+//
+// Wrap the transport of the http.Client passed to spiget.NewClient:
+//
+//	client := spiget.NewClient(&http.Client{
+//		Transport: spigetotel.NewTransport(nil),
+//	})
+//
+// Spans are children of the span in the context passed to the service
+// methods and are named after the method, e.g. "Resources.Get".
+package spigetotel
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sunxyw/go-spiget/spiget"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/sunxyw/go-spiget/otel"
+
+// Attribute keys set on spans and metrics.
+const (
+	OperationKey  = attribute.Key("spiget.operation")
+	ResourceIDKey = attribute.Key("spiget.resource.id")
+	VersionIDKey  = attribute.Key("spiget.version.id")
+	VersionsKey   = attribute.Key("spiget.versions")
+	AuthorIDKey   = attribute.Key("spiget.author.id")
+	CategoryIDKey = attribute.Key("spiget.category.id")
+	QueryKey      = attribute.Key("spiget.search.query")
+	WebhookIDKey  = attribute.Key("spiget.webhook.id")
+	PageSizeKey   = attribute.Key("spiget.page.size")
+	PageKey       = attribute.Key("spiget.page.index")
+	PageCountKey  = attribute.Key("spiget.page.count")
+
+	methodKey     = attribute.Key("http.request.method")
+	statusCodeKey = attribute.Key("http.response.status_code")
+	serverKey     = attribute.Key("server.address")
+	errorTypeKey  = attribute.Key("error.type")
+)
+
+// paramKeys maps the parameters of spiget.Endpoint to attribute keys.
+var paramKeys = map[string]attribute.Key{
+	"resource": ResourceIDKey,
+	"version":  VersionIDKey,
+	"versions": VersionsKey,
+	"author":   AuthorIDKey,
+	"category": CategoryIDKey,
+	"query":    QueryKey,
+	"webhook":  WebhookIDKey,
+}
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// Option configures a Transport.
+type Option func(*config)
+
+// WithTracerProvider sets the TracerProvider spans are created with. The
+// global provider is used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = tp }
+}
+
+// WithMeterProvider sets the MeterProvider metrics are recorded with. The
+// global provider is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = mp }
+}
+
+// WithPropagators sets the propagators used to inject the span context into
+// the request headers. The global propagators are used by default.
+func WithPropagators(p propagation.TextMapPropagator) Option {
+	return func(c *config) { c.propagators = p }
+}
+
+// Transport is an http.RoundTripper creating a span and recording metrics
+// for every request made to the Spiget API.
+//
+// The following metrics are recorded, all with the spiget.operation
+// attribute:
+//
+//	spiget.client.requests          number of requests
+//	spiget.client.request.duration  latency in seconds until the response headers were received
+//	spiget.client.errors            failed requests by http.response.status_code or error.type
+type Transport struct {
+	base        http.RoundTripper
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+
+	requests metric.Int64Counter
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+// NewTransport returns a Transport wrapping base. If base is nil,
+// http.DefaultTransport is used.
+func NewTransport(base http.RoundTripper, opts ...Option) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	t := &Transport{
+		base:        base,
+		tracer:      c.tracerProvider.Tracer(instrumentationName),
+		propagators: c.propagators,
+	}
+
+	// The instruments are never nil, even if creating them fails, so errors
+	// are reported to the global handler instead of being returned.
+	meter := c.meterProvider.Meter(instrumentationName)
+	var err error
+	if t.requests, err = meter.Int64Counter("spiget.client.requests",
+		metric.WithDescription("Number of requests made to the Spiget API."),
+		metric.WithUnit("{request}")); err != nil {
+		otel.Handle(err)
+	}
+	if t.duration, err = meter.Float64Histogram("spiget.client.request.duration",
+		metric.WithDescription("Duration of requests made to the Spiget API."),
+		metric.WithUnit("s")); err != nil {
+		otel.Handle(err)
+	}
+	if t.errors, err = meter.Int64Counter("spiget.client.errors",
+		metric.WithDescription("Number of failed requests made to the Spiget API."),
+		metric.WithUnit("{request}")); err != nil {
+		otel.Handle(err)
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper. The span ends when the response
+// headers are received; reading the body is not included.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := spiget.EndpointOf(req)
+	name := endpoint.Operation
+	if name == "" {
+		name = "HTTP " + req.Method
+	}
+
+	attrs := []attribute.KeyValue{
+		OperationKey.String(endpoint.Operation),
+		methodKey.String(req.Method),
+		serverKey.String(req.URL.Hostname()),
+	}
+	metricAttrs := metric.WithAttributes(attrs[0])
+
+	for param, value := range endpoint.Params {
+		key := paramKeys[param]
+		if key == "" {
+			continue
+		}
+		if id, err := strconv.Atoi(value); err == nil {
+			attrs = append(attrs, key.Int(id))
+		} else {
+			attrs = append(attrs, key.String(value))
+		}
+	}
+	query := req.URL.Query()
+	if size, err := strconv.Atoi(query.Get("size")); err == nil {
+		attrs = append(attrs, PageSizeKey.Int(size))
+	}
+	if page, err := strconv.Atoi(query.Get("page")); err == nil {
+		attrs = append(attrs, PageKey.Int(page))
+	}
+
+	ctx, span := t.tracer.Start(req.Context(), name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	// A RoundTripper must not modify the request, so the propagation
+	// headers are injected into a clone.
+	req = req.Clone(ctx)
+	t.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	t.requests.Add(ctx, 1, metricAttrs)
+	t.duration.Record(ctx, time.Since(start).Seconds(), metricAttrs)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		t.errors.Add(ctx, 1, metric.WithAttributes(attrs[0], errorTypeKey.String("transport")))
+		return nil, err
+	}
+
+	span.SetAttributes(statusCodeKey.Int(resp.StatusCode))
+	if page, err := strconv.Atoi(resp.Header.Get("X-Page-Index")); err == nil {
+		span.SetAttributes(PageKey.Int(page))
+	}
+	if count, err := strconv.Atoi(resp.Header.Get("X-Page-Count")); err == nil {
+		span.SetAttributes(PageCountKey.Int(count))
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		t.errors.Add(ctx, 1, metric.WithAttributes(attrs[0], statusCodeKey.Int(resp.StatusCode)))
+	}
+	return resp, nil
+}
@@ -0,0 +1,246 @@
+package spigetotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sunxyw/go-spiget/spiget"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// setup returns a client instrumented with a Transport recording to the
+// returned span recorder and metric reader. The server answers /v2/resources/1
+// and its reviews, /v2/search/authors/ and webhook requests, and 404
+// otherwise. Its traceparent headers are sent on headers.
+func setup(t *testing.T) (*spiget.Client, *sdktrace.TracerProvider, *tracetest.SpanRecorder, *sdkmetric.ManualReader, chan string) {
+	headers := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Get("traceparent")
+		switch {
+		case r.URL.Path == "/v2/resources/1":
+			w.Write([]byte(`{"id": 1}`))
+		case r.URL.Path == "/v2/resources/1/reviews":
+			w.Header().Set("X-Page-Index", "2")
+			w.Header().Set("X-Page-Count", "5")
+			w.Write([]byte(`[]`))
+		case strings.HasPrefix(r.URL.Path, "/v2/search/authors/"), strings.HasPrefix(r.URL.Path, "/v2/webhook/"):
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	transport := NewTransport(nil,
+		WithTracerProvider(tp),
+		WithMeterProvider(mp),
+		WithPropagators(propagation.TraceContext{}),
+	)
+	client, err := spiget.New(
+		spiget.WithHTTPClient(&http.Client{Transport: transport}),
+		spiget.WithBaseURL(server.URL+"/v2/"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, tp, recorder, reader, headers
+}
+
+// spanAttrs returns the attributes of s by key.
+func spanAttrs(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range s.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTransportSpans(t *testing.T) {
+	client, tp, recorder, _, headers := setup(t)
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+
+	if _, _, err := client.Resources.Get(ctx, 1); err != nil {
+		t.Fatalf("Resources.Get returned error: %v", err)
+	}
+	if _, _, err := client.Resources.GetReviews(ctx, 1, spiget.ListOptions{Size: 10, Page: 2}); err != nil {
+		t.Fatalf("Resources.GetReviews returned error: %v", err)
+	}
+	if _, _, err := client.Authors.Search(ctx, "md_5", nil); err != nil {
+		t.Fatalf("Authors.Search returned error: %v", err)
+	}
+	if _, err := client.Webhook.Delete(ctx, spiget.Webhook{ID: "hook", Secret: "s3cret"}); err != nil {
+		t.Fatalf("Webhook.Delete returned error: %v", err)
+	}
+	if _, _, err := client.Resources.Get(ctx, 2); err == nil {
+		t.Fatal("Resources.Get of a missing resource returned no error")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 6 {
+		t.Fatalf("recorded %d spans, want 6", len(spans))
+	}
+	tests := []struct {
+		name  string
+		attrs map[attribute.Key]attribute.Value
+		code  codes.Code
+	}{
+		{"Resources.Get", map[attribute.Key]attribute.Value{
+			ResourceIDKey: attribute.IntValue(1), statusCodeKey: attribute.IntValue(200),
+		}, codes.Unset},
+		{"Resources.GetReviews", map[attribute.Key]attribute.Value{
+			ResourceIDKey: attribute.IntValue(1), PageSizeKey: attribute.IntValue(10),
+			PageKey: attribute.IntValue(2), PageCountKey: attribute.IntValue(5),
+		}, codes.Unset},
+		{"Authors.Search", map[attribute.Key]attribute.Value{
+			QueryKey: attribute.StringValue("md_5"), methodKey: attribute.StringValue("GET"),
+		}, codes.Unset},
+		{"Webhook.Delete", map[attribute.Key]attribute.Value{
+			WebhookIDKey: attribute.StringValue("hook"), methodKey: attribute.StringValue("DELETE"),
+		}, codes.Unset},
+		{"Resources.Get", map[attribute.Key]attribute.Value{
+			ResourceIDKey: attribute.IntValue(2), statusCodeKey: attribute.IntValue(404),
+		}, codes.Error},
+	}
+	for i, tt := range tests {
+		span := spans[i]
+		if span.Name() != tt.name {
+			t.Errorf("span %d is named %q, want %q", i, span.Name(), tt.name)
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s has parent %v, want %v", span.Name(), span.Parent().SpanID(), parent.SpanContext().SpanID())
+		}
+		if span.Status().Code != tt.code {
+			t.Errorf("span %s has status %v, want %v", span.Name(), span.Status().Code, tt.code)
+		}
+		attrs := spanAttrs(span)
+		if got := attrs[OperationKey].AsString(); got != tt.name {
+			t.Errorf("span %s has operation %q", span.Name(), got)
+		}
+		for k, want := range tt.attrs {
+			if got := attrs[k]; got != want {
+				t.Errorf("span %s has %s = %v, want %v", span.Name(), k, got.Emit(), want.Emit())
+			}
+		}
+		for k, v := range attrs {
+			if strings.Contains(v.Emit(), "s3cret") {
+				t.Errorf("span %s leaks the webhook secret in %s", span.Name(), k)
+			}
+		}
+
+		header := <-headers
+		if want := span.SpanContext().SpanID().String(); !strings.Contains(header, want) {
+			t.Errorf("request of span %s has traceparent %q, want span ID %s", span.Name(), header, want)
+		}
+	}
+}
+
+func TestTransportMetrics(t *testing.T) {
+	client, _, _, reader, _ := setup(t)
+	ctx := context.Background()
+
+	client.Resources.Get(ctx, 1)
+	client.Resources.Get(ctx, 1)
+	client.Resources.Get(ctx, 2)
+	client.Authors.Search(ctx, "md_5", nil)
+
+	metrics := collect(t, reader)
+	requests := counts(t, metrics["spiget.client.requests"], OperationKey)
+	if requests["Resources.Get"] != 3 || requests["Authors.Search"] != 1 {
+		t.Errorf("requests by operation are %v, want 3 Resources.Get and 1 Authors.Search", requests)
+	}
+	errs := counts(t, metrics["spiget.client.errors"], statusCodeKey)
+	if len(errs) != 1 || errs["404"] != 1 {
+		t.Errorf("errors by status are %v, want one 404", errs)
+	}
+	duration, ok := metrics["spiget.client.request.duration"].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("spiget.client.request.duration is %T, want a histogram", metrics["spiget.client.request.duration"])
+	}
+	var n uint64
+	for _, dp := range duration.DataPoints {
+		n += dp.Count
+	}
+	if n != 4 {
+		t.Errorf("recorded %d durations, want 4", n)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransportError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	refused := errors.New("connection refused")
+	transport := NewTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, refused
+	}),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+
+	req, _ := http.NewRequest("GET", "https://api.spiget.org/v2/resources/1", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, refused) {
+		t.Fatalf("RoundTrip returned error %v, want %v", err, refused)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error {
+		t.Fatalf("recorded spans %v, want one failed span", spans)
+	}
+	errs := counts(t, collect(t, reader)["spiget.client.errors"], errorTypeKey)
+	if len(errs) != 1 || errs["transport"] != 1 {
+		t.Errorf("errors by type are %v, want one transport error", errs)
+	}
+}
+
+// collect returns the metrics of reader by name.
+func collect(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+// counts returns the values of an int64 counter by the value of key.
+func counts(t *testing.T, data metricdata.Aggregation, key attribute.Key) map[string]int64 {
+	t.Helper()
+	sum, ok := data.(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("metric is %T, want an int64 sum", data)
+	}
+	counts := make(map[string]int64)
+	for _, dp := range sum.DataPoints {
+		v, _ := dp.Attributes.Value(key)
+		counts[v.Emit()] += dp.Value
+	}
+	return counts
+}
@@ -0,0 +1,239 @@
+// Package releasecheck cross-checks the versions of Spiget resources against
+// the GitHub releases of their source repositories.
+//
+// It reports versions only published on one side, whether the latest
+// versions agree, and whether the jar downloaded from the Spiget CDN is
+// identical to the one attached to the GitHub release.
+package releasecheck
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+// ErrNoGitHubRepository is returned for resources not linking a GitHub
+// repository.
+var ErrNoGitHubRepository = errors.New("resource does not link a GitHub repository")
+
+// ErrDigestMismatch is returned if a jar downloaded from GitHub does not
+// match the digest GitHub computed for the asset.
+var ErrDigestMismatch = errors.New("release asset does not match its digest")
+
+// Checker cross-checks resources.
+type Checker struct {
+	Spiget *spiget.Client
+	GitHub *GitHub
+}
+
+// Options specifies the optional parameters to Checker.Check.
+type Options struct {
+	// VerifyChecksum downloads the latest version of the resource from the
+	// Spiget CDN and the jar of the matching release from GitHub and
+	// compares their SHA-256 checksums. The jar is verified against the
+	// SHA-256 digest of the asset, if GitHub reports one.
+	VerifyChecksum bool
+
+	// IncludePrereleases matches versions against prereleases, too.
+	IncludePrereleases bool
+}
+
+// Match is a Spiget version with the release it was published as.
+type Match struct {
+	Version *spiget.Version
+	Release *Release
+}
+
+// Checksum is the result of comparing the Spiget download of a version with
+// the jar of its release.
+type Checksum struct {
+	Version *spiget.Version
+	Asset   *Asset
+
+	// Spiget and GitHub are the hex-encoded SHA-256 checksums.
+	Spiget string
+	GitHub string
+}
+
+// Mismatch reports whether the files differ.
+func (c *Checksum) Mismatch() bool {
+	return c.Spiget != c.GitHub
+}
+
+// Report is the result of cross-checking a resource.
+type Report struct {
+	Resource   *spiget.Resource
+	Repository spiget.Link
+
+	Matches []Match
+
+	// SpigetOnly are versions without a matching release, GitHubOnly are
+	// releases without a matching version.
+	SpigetOnly []*spiget.Version
+	GitHubOnly []*Release
+
+	// LatestVersion and LatestRelease are the newest version on Spiget and
+	// the newest release on GitHub.
+	LatestVersion *spiget.Version
+	LatestRelease *Release
+
+	// Checksum is set if Options.VerifyChecksum was given and the latest
+	// version has a matching release with a jar.
+	Checksum *Checksum
+}
+
+// Drift reports whether the latest version on Spiget differs from the latest
+// release on GitHub.
+func (r *Report) Drift() bool {
+	if r.LatestVersion == nil || r.LatestRelease == nil {
+		return (r.LatestVersion == nil) != (r.LatestRelease == nil)
+	}
+	return !releaseMatches(r.LatestRelease, r.LatestVersion.Name)
+}
+
+// Check cross-checks the resource against the releases of the GitHub
+// repository found by spiget.Resource.SourceRepository.
+func (c *Checker) Check(ctx context.Context, id int, opts *Options) (*Report, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	resource, _, err := c.Spiget.Resources.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	repo, ok := resource.SourceRepository()
+	if !ok || repo.Kind != spiget.LinkGitHub {
+		return nil, ErrNoGitHubRepository
+	}
+
+	versions, err := c.versions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	releases, err := c.GitHub.ListReleases(ctx, repo.Owner, repo.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.IncludePrereleases {
+		stable := releases[:0]
+		for _, r := range releases {
+			if !r.Prerelease {
+				stable = append(stable, r)
+			}
+		}
+		releases = stable
+	}
+
+	report := &Report{Resource: resource, Repository: repo}
+	matched := make(map[*Release]bool)
+	for _, v := range versions {
+		if report.LatestVersion == nil || v.ReleaseDate > report.LatestVersion.ReleaseDate {
+			report.LatestVersion = v
+		}
+		release := findRelease(releases, v.Name)
+		if release == nil {
+			report.SpigetOnly = append(report.SpigetOnly, v)
+			continue
+		}
+		matched[release] = true
+		report.Matches = append(report.Matches, Match{Version: v, Release: release})
+	}
+	for _, r := range releases {
+		if report.LatestRelease == nil || r.PublishedAt.After(report.LatestRelease.PublishedAt) {
+			report.LatestRelease = r
+		}
+		if !matched[r] {
+			report.GitHubOnly = append(report.GitHubOnly, r)
+		}
+	}
+
+	if opts.VerifyChecksum && report.LatestVersion != nil {
+		if err := c.verify(ctx, report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// versions lists all versions of the resource.
+func (c *Checker) versions(ctx context.Context, id int) ([]*spiget.Version, error) {
+	var all []*spiget.Version
+	opts := spiget.ListOptions{Size: 100, Page: 1}
+	for {
+		versions, resp, err := c.Spiget.Resources.GetVersions(ctx, id, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, versions...)
+		if len(versions) == 0 || resp.NextPage > resp.LastPage {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// verify compares the checksums of the latest version and its release.
+func (c *Checker) verify(ctx context.Context, report *Report) error {
+	var asset *Asset
+	for _, m := range report.Matches {
+		if m.Version == report.LatestVersion {
+			asset = m.Release.Jar()
+		}
+	}
+	if asset == nil {
+		return nil
+	}
+
+	spigetHash := sha256.New()
+	if _, err := c.Spiget.Resources.DownloadTo(ctx, report.Resource.ID, spigetHash); err != nil {
+		return err
+	}
+	githubHash := sha256.New()
+	if err := c.GitHub.Download(ctx, asset, githubHash); err != nil {
+		return err
+	}
+	githubSum := hex.EncodeToString(githubHash.Sum(nil))
+	if digest, ok := asset.SHA256(); ok && digest != githubSum {
+		return fmt.Errorf("%w: %s has SHA-256 %s, GitHub reports %s", ErrDigestMismatch, asset.Name, githubSum, digest)
+	}
+
+	report.Checksum = &Checksum{
+		Version: report.LatestVersion,
+		Asset:   asset,
+		Spiget:  hex.EncodeToString(spigetHash.Sum(nil)),
+		GitHub:  githubSum,
+	}
+	return nil
+}
+
+// findRelease returns the release published as the version name, or nil.
+func findRelease(releases []*Release, name string) *Release {
+	for _, r := range releases {
+		if releaseMatches(r, name) {
+			return r
+		}
+	}
+	return nil
+}
+
+// releaseMatches reports whether the tag or name of r is the version name.
+func releaseMatches(r *Release, name string) bool {
+	v := normalizeVersion(name)
+	return v != "" && (normalizeVersion(r.TagName) == v || normalizeVersion(r.Name) == v)
+}
+
+// normalizeVersion strips the decoration commonly added to version names,
+// e.g. "Version 1.2" and "v1.2" both become "1.2".
+func normalizeVersion(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "version")
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "v")
+	return s
+}
@@ -0,0 +1,199 @@
+package releasecheck
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+// fakeAPIs serves the Spiget API below /spiget/, the GitHub API below
+// /github/ and release assets below /assets/.
+type fakeAPIs struct {
+	*httptest.Server
+
+	versions []*spiget.Version
+	releases []*Release
+	jar      []byte // served by Spiget
+	assetJar []byte // served by GitHub
+}
+
+func newFakeAPIs(t *testing.T, versions []*spiget.Version, releases []*Release) *fakeAPIs {
+	f := &fakeAPIs{versions: versions, releases: releases, jar: []byte("jar content"), assetJar: []byte("jar content")}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/spiget/resources/1":
+			fmt.Fprint(w, `{"id": 1, "sourceCodeLink": "https://github.com/owner/repo"}`)
+		case "/spiget/resources/2":
+			fmt.Fprint(w, `{"id": 2, "sourceCodeLink": "https://gitlab.com/owner/repo"}`)
+		case "/spiget/resources/1/versions":
+			json.NewEncoder(w).Encode(f.versions)
+		case "/spiget/resources/1/download":
+			w.Write(f.jar)
+		case "/assets/plugin.jar":
+			w.Write(f.assetJar)
+		case "/github/repos/owner/repo/releases":
+			json.NewEncoder(w).Encode(f.releases)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeAPIs) checker(t *testing.T) *Checker {
+	client, err := spiget.New(spiget.WithBaseURL(f.URL + "/spiget/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := url.Parse(f.URL + "/github/")
+	return &Checker{Spiget: client, GitHub: &GitHub{BaseURL: base}}
+}
+
+func (f *fakeAPIs) asset() *Asset {
+	sum := sha256.Sum256(f.assetJar)
+	return &Asset{Name: "plugin.jar", BrowserDownloadURL: f.URL + "/assets/plugin.jar", Digest: "sha256:" + hex.EncodeToString(sum[:])}
+}
+
+var day = 24 * time.Hour
+
+func version(name string, released time.Time) *spiget.Version {
+	return &spiget.Version{Name: name, ReleaseDate: int(released.Unix())}
+}
+
+func release(tag string, published time.Time, assets ...*Asset) *Release {
+	return &Release{TagName: tag, PublishedAt: published, Assets: assets}
+}
+
+func TestCheckSame(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	f := newFakeAPIs(t, nil, nil)
+	f.versions = []*spiget.Version{version("1.0", now.Add(-2*day)), version("Version 1.1", now)}
+	f.releases = []*Release{release("v1.1", now, f.asset()), release("v1.0", now.Add(-2*day))}
+
+	report, err := f.checker(t).Check(context.Background(), 1, &Options{VerifyChecksum: true})
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if report.Drift() {
+		t.Errorf("Drift reported for latest version %q and release %q", report.LatestVersion.Name, report.LatestRelease.TagName)
+	}
+	if len(report.Matches) != 2 || len(report.SpigetOnly) != 0 || len(report.GitHubOnly) != 0 {
+		t.Errorf("Check returned %d matches, %d Spiget only, %d GitHub only, want 2, 0, 0", len(report.Matches), len(report.SpigetOnly), len(report.GitHubOnly))
+	}
+	if report.Repository.Owner != "owner" || report.Repository.Repo != "repo" {
+		t.Errorf("Check found repository %s/%s, want owner/repo", report.Repository.Owner, report.Repository.Repo)
+	}
+	if report.Checksum == nil || report.Checksum.Mismatch() {
+		t.Errorf("Check returned checksum %+v, want matching checksums", report.Checksum)
+	}
+}
+
+func TestCheckNewerRelease(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	f := newFakeAPIs(t, nil, nil)
+	f.versions = []*spiget.Version{version("1.0", now.Add(-2*day))}
+	f.releases = []*Release{release("v1.1", now), release("v1.0", now.Add(-2*day))}
+
+	report, err := f.checker(t).Check(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if !report.Drift() {
+		t.Error("no drift reported for a newer release on GitHub")
+	}
+	if len(report.GitHubOnly) != 1 || report.GitHubOnly[0].TagName != "v1.1" {
+		t.Errorf("Check returned GitHubOnly %+v, want v1.1", report.GitHubOnly)
+	}
+}
+
+func TestCheckPrerelease(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	f := newFakeAPIs(t, nil, nil)
+	f.versions = []*spiget.Version{version("1.0", now.Add(-2*day))}
+	beta := release("v1.1-beta", now)
+	beta.Prerelease = true
+	f.releases = []*Release{beta, release("v1.0", now.Add(-2*day))}
+
+	report, err := f.checker(t).Check(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if report.Drift() || len(report.GitHubOnly) != 0 {
+		t.Errorf("prerelease was not ignored: drift %v, GitHubOnly %+v", report.Drift(), report.GitHubOnly)
+	}
+
+	report, err = f.checker(t).Check(context.Background(), 1, &Options{IncludePrereleases: true})
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if !report.Drift() || len(report.GitHubOnly) != 1 {
+		t.Errorf("prerelease was ignored with IncludePrereleases: drift %v, GitHubOnly %+v", report.Drift(), report.GitHubOnly)
+	}
+}
+
+func TestCheckMissingAsset(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	f := newFakeAPIs(t, nil, nil)
+	f.versions = []*spiget.Version{version("1.0", now)}
+	f.releases = []*Release{release("1.0", now, &Asset{Name: "sources.zip"})}
+
+	report, err := f.checker(t).Check(context.Background(), 1, &Options{VerifyChecksum: true})
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if report.Checksum != nil {
+		t.Errorf("Check returned checksum %+v for a release without a jar", report.Checksum)
+	}
+}
+
+func TestCheckDigestMismatch(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	f := newFakeAPIs(t, nil, nil)
+	asset := f.asset()
+	asset.Digest = "sha256:0000"
+	f.versions = []*spiget.Version{version("1.0", now)}
+	f.releases = []*Release{release("1.0", now, asset)}
+
+	if _, err := f.checker(t).Check(context.Background(), 1, &Options{VerifyChecksum: true}); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("Check returned error %v, want %v", err, ErrDigestMismatch)
+	}
+}
+
+func TestCheckChecksumMismatch(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	f := newFakeAPIs(t, nil, nil)
+	f.assetJar = []byte("rebuilt jar content")
+	f.versions = []*spiget.Version{version("1.0", now)}
+	f.releases = []*Release{release("1.0", now, f.asset())}
+
+	report, err := f.checker(t).Check(context.Background(), 1, &Options{VerifyChecksum: true})
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if report.Checksum == nil || !report.Checksum.Mismatch() {
+		t.Fatalf("Check returned checksum %+v, want a mismatch", report.Checksum)
+	}
+	spigetSum, githubSum := sha256.Sum256(f.jar), sha256.Sum256(f.assetJar)
+	if report.Checksum.Spiget != hex.EncodeToString(spigetSum[:]) || report.Checksum.GitHub != hex.EncodeToString(githubSum[:]) {
+		t.Errorf("Check returned checksums %s and %s, want the checksums of the Spiget and GitHub jars", report.Checksum.Spiget, report.Checksum.GitHub)
+	}
+}
+
+func TestCheckNoGitHubRepository(t *testing.T) {
+	f := newFakeAPIs(t, nil, nil)
+	if _, err := f.checker(t).Check(context.Background(), 2, nil); err != ErrNoGitHubRepository {
+		t.Errorf("Check returned error %v, want %v", err, ErrNoGitHubRepository)
+	}
+}
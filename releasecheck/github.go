@@ -0,0 +1,168 @@
+package releasecheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGitHubURL = "https://api.github.com/"
+
+	// maxReleasePages limits the number of pages of releases listed.
+	maxReleasePages = 5
+)
+
+// HTTPDoer sends HTTP requests. *http.Client implements it; tests may
+// provide a fake.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// GitHub lists releases and downloads release assets using the GitHub REST
+// API.
+type GitHub struct {
+	// HTTP sends the requests. Defaults to http.DefaultClient.
+	HTTP HTTPDoer
+
+	// BaseURL of the GitHub API, e.g. of a fake in tests or a GitHub
+	// Enterprise instance. Defaults to https://api.github.com/.
+	BaseURL *url.URL
+
+	// Token, if set, authenticates requests to BaseURL, which raises the
+	// rate limit.
+	Token string
+}
+
+// Release is a GitHub release.
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	HTMLURL     string    `json:"html_url"`
+	Assets      []*Asset  `json:"assets"`
+}
+
+// Asset is a file attached to a GitHub release.
+type Asset struct {
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	ContentType        string `json:"content_type"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+
+	// Digest is the checksum computed by GitHub, e.g. "sha256:...". It is
+	// empty for assets uploaded before GitHub started computing digests.
+	Digest string `json:"digest"`
+}
+
+// SHA256 returns the hex-encoded SHA-256 digest of the asset, if GitHub
+// computed one.
+func (a *Asset) SHA256() (string, bool) {
+	if !strings.HasPrefix(a.Digest, "sha256:") || len(a.Digest) == len("sha256:") {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimPrefix(a.Digest, "sha256:")), true
+}
+
+// Jar returns the first asset of the release that is a jar file, or nil.
+func (r *Release) Jar() *Asset {
+	for _, a := range r.Assets {
+		if strings.HasSuffix(strings.ToLower(a.Name), ".jar") {
+			return a
+		}
+	}
+	return nil
+}
+
+// linkNextRE matches the URL of the next page in a Link header.
+var linkNextRE = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// ListReleases lists the published releases of the repository, newest
+// first. Drafts are skipped.
+func (g *GitHub) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	u, err := g.baseURL().Parse("repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/releases?per_page=100")
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []*Release
+	next := u.String()
+	for page := 0; next != "" && page < maxReleasePages; page++ {
+		resp, err := g.get(ctx, next, "application/vnd.github+json")
+		if err != nil {
+			return nil, err
+		}
+		var batch []*Release
+		err = json.NewDecoder(resp.Body).Decode(&batch)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range batch {
+			if !r.Draft {
+				releases = append(releases, r)
+			}
+		}
+
+		next = ""
+		if m := linkNextRE.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+			next = m[1]
+		}
+	}
+	return releases, nil
+}
+
+// Download writes the content of the asset to w.
+func (g *GitHub) Download(ctx context.Context, asset *Asset, w io.Writer) error {
+	resp, err := g.get(ctx, asset.BrowserDownloadURL, "application/octet-stream")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func (g *GitHub) baseURL() *url.URL {
+	if g.BaseURL != nil {
+		return g.BaseURL
+	}
+	u, _ := url.Parse(defaultGitHubURL)
+	return u
+}
+
+// get sends a GET request and returns the response if it has status 200.
+func (g *GitHub) get(ctx context.Context, u, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	// The token is only sent to the API, not to the hosts of release assets
+	// or other URLs taken from responses.
+	if g.Token != "" && strings.EqualFold(req.URL.Host, g.baseURL().Host) {
+		req.Header.Set("Authorization", "Bearer "+g.Token)
+	}
+
+	doer := g.HTTP
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+	resp, err := doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", req.URL.Redacted(), resp.Status)
+	}
+	return resp, nil
+}
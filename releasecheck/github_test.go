@@ -0,0 +1,55 @@
+package releasecheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGitHubToken(t *testing.T) {
+	auth := make(map[string]string)
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth["other"+r.URL.Path] = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/releases":
+			fmt.Fprint(w, `[]`)
+		default:
+			fmt.Fprint(w, "jar")
+		}
+	}))
+	defer other.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth["api"+r.URL.Path] = r.Header.Get("Authorization")
+		w.Header().Set("Link", `<`+other.URL+`/releases>; rel="next"`)
+		fmt.Fprint(w, `[{"tag_name": "v1.0"}]`)
+	}))
+	defer api.Close()
+
+	base, _ := url.Parse(api.URL + "/")
+	g := &GitHub{BaseURL: base, Token: "token"}
+	releases, err := g.ListReleases(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("ListReleases returned error: %v", err)
+	}
+	if len(releases) != 1 {
+		t.Errorf("ListReleases returned %d releases, want 1", len(releases))
+	}
+	if err := g.Download(context.Background(), &Asset{BrowserDownloadURL: other.URL + "/plugin.jar"}, io.Discard); err != nil {
+		t.Fatalf("Download returned error: %v", err)
+	}
+
+	want := map[string]string{
+		"api/repos/owner/repo/releases": "Bearer token",
+		"other/releases":                "",
+		"other/plugin.jar":              "",
+	}
+	for path, w := range want {
+		if got, ok := auth[path]; !ok || got != w {
+			t.Errorf("request to %s sent Authorization %q, want %q", path, got, w)
+		}
+	}
+}
@@ -16,7 +16,9 @@ type Author struct {
 	ID         int               `json:"id,omitempty"`
 	Name       string            `json:"name,omitempty"`
 	Icon       Icon              `json:"icon,omitempty"`
-	Identities map[string]string // may not be present
+	Identities map[string]string `json:"identities,omitempty"` // may not be present
+
+	present fieldMask // fields present in the decoded JSON object
 }
 
 func (a *Author) String() string {
@@ -29,6 +31,13 @@ type AuthorListOptions struct {
 	ListOptions
 }
 
+func (o AuthorListOptions) validate() error {
+	if err := authorSortFields.validate("authors", o.Sort, o.Order); err != nil {
+		return err
+	}
+	return authorFields.validate("author", o.Fields)
+}
+
 // Get a list of available authors.
 // Note: This only includes members involved with resources, either being their author
 // or having reviewed a resource.
@@ -75,19 +84,89 @@ func (a *AuthorsService) Get(ctx context.Context, id int) (*Author, *Response, e
 	return author, resp, nil
 }
 
+// Get the resources of an author.
+//
+// Spiget API docs: https://spiget.org/documentation/#!/authors/get_authors_author_resources
+func (a *AuthorsService) ListResources(ctx context.Context, id int, opts *ResourceListOptions) ([]*Resource, *Response, error) {
+	u := "authors/" + strconv.Itoa(id) + "/resources"
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := a.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resources []*Resource
+	resp, err := a.client.Do(ctx, req, &resources)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return resources, resp, nil
+}
+
+// AuthorResult is the outcome of fetching a single author with
+// AuthorsService.GetMany.
+type AuthorResult struct {
+	ID       int
+	Author   *Author
+	Response *Response
+	Err      error
+}
+
+// GetMany fetches the authors with the given IDs concurrently. Duplicate IDs
+// are only fetched once.
+//
+// The results are returned in the order of ids, each carrying its own error.
+// If ctx is canceled before all authors were fetched, the results fetched so
+// far are returned along with ctx.Err().
+func (a *AuthorsService) GetMany(ctx context.Context, ids []int, opts *BatchOptions) ([]*AuthorResult, error) {
+	batch, err := runBatch(ctx, ids, opts, func(ctx context.Context, id int) (interface{}, *Response, error) {
+		return a.Get(ctx, id)
+	})
+	if batch == nil {
+		return nil, err
+	}
+
+	results := make([]*AuthorResult, len(batch))
+	for i, b := range batch {
+		author, _ := b.v.(*Author)
+		results[i] = &AuthorResult{ID: b.id, Author: author, Response: b.resp, Err: b.err}
+	}
+	return results, err
+}
+
 // AuthorSearchOptions specifies the optional parameters to the
 // AuthorsService.Search method.
 type AuthorSearchOptions struct {
-	Field string `url:"field,omitempty"`
+	// Field to search in. Defaults to the author name.
+	Field SearchField `url:"field,omitempty"`
 
 	ListOptions
 }
 
+func (o AuthorSearchOptions) validate() error {
+	if err := authorSearchFields.validate("authors", o.Field); err != nil {
+		return err
+	}
+	if err := authorSortFields.validate("authors", o.Sort, o.Order); err != nil {
+		return err
+	}
+	return authorFields.validate("author", o.Fields)
+}
+
 // Search searches for authors by specified field.
 //
 // Spiget API docs: https://spiget.org/documentation/#!/authors/get_search_authors_query
 func (a *AuthorsService) Search(ctx context.Context, query string, opts *AuthorSearchOptions) ([]*Author, *Response, error) {
-	u := "search/authors/" + query
+	if query == "" {
+		return nil, nil, errEmptyQuery
+	}
+
+	u := "search/authors/" + pathEscape(query)
 	u, err := addOptions(u, opts)
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,92 @@
+package spiget
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultBatchWorkers = 4
+
+// BatchOptions specifies the optional parameters to the GetMany methods.
+type BatchOptions struct {
+	// Workers is the number of requests sent concurrently. Defaults to 4.
+	Workers int
+}
+
+// batchResult is the outcome of fetching a single ID of a batch.
+type batchResult struct {
+	id   int
+	v    interface{}
+	resp *Response
+	err  error
+}
+
+// batchFetchFunc fetches the object with the given ID.
+type batchFetchFunc func(ctx context.Context, id int) (interface{}, *Response, error)
+
+// runBatch calls fetch once for every distinct ID in ids, using a pool of
+// opts.Workers goroutines. Requests still pass through the client, so its
+// RateLimiter applies to each of them.
+//
+// The results are returned in the order of ids, with duplicate IDs sharing
+// the same result. If ctx is done before all IDs were fetched, the results of
+// the remaining IDs carry ctx.Err(). ctx.Err() is returned whenever ctx is
+// done by the time all workers finished.
+func runBatch(ctx context.Context, ids []int, opts *BatchOptions, fetch batchFetchFunc) ([]*batchResult, error) {
+	if ctx == nil {
+		return nil, errNonNilContext
+	}
+
+	workers := defaultBatchWorkers
+	if opts != nil && opts.Workers > 0 {
+		workers = opts.Workers
+	}
+
+	var distinct []*batchResult
+	byID := make(map[int]*batchResult, len(ids))
+	for _, id := range ids {
+		if _, ok := byID[id]; !ok {
+			res := &batchResult{id: id}
+			byID[id] = res
+			distinct = append(distinct, res)
+		}
+	}
+	if workers > len(distinct) {
+		workers = len(distinct)
+	}
+
+	queue := make(chan *batchResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for res := range queue {
+				res.v, res.resp, res.err = fetch(ctx, res.id)
+			}
+		}()
+	}
+
+	var skipErr error
+	for _, res := range distinct {
+		if skipErr == nil {
+			select {
+			case queue <- res:
+				continue
+			case <-ctx.Done():
+				skipErr = ctx.Err()
+			}
+		}
+		res.err = skipErr
+	}
+	close(queue)
+	wg.Wait()
+
+	results := make([]*batchResult, len(ids))
+	for i, id := range ids {
+		results[i] = byID[id]
+	}
+	// ctx may be done after the last ID was queued, failing the requests
+	// still in flight.
+	return results, ctx.Err()
+}
@@ -0,0 +1,192 @@
+package spiget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBatch(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[int]int)
+	ids := []int{3, 1, 2, 1, 3, 4}
+	results, err := runBatch(context.Background(), ids, nil, func(ctx context.Context, id int) (interface{}, *Response, error) {
+		mu.Lock()
+		calls[id]++
+		mu.Unlock()
+		if id == 2 {
+			return nil, nil, errors.New("failed")
+		}
+		return id * 10, nil, nil
+	})
+	if err != nil {
+		t.Fatalf("runBatch returned error: %v", err)
+	}
+
+	if want := map[int]int{1: 1, 2: 1, 3: 1, 4: 1}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("fetch was called %v times by ID, want once per ID", calls)
+	}
+	if len(results) != len(ids) {
+		t.Fatalf("runBatch returned %d results, want %d", len(results), len(ids))
+	}
+	for i, res := range results {
+		if res.id != ids[i] {
+			t.Errorf("result %d has ID %d, want %d", i, res.id, ids[i])
+		}
+		if ids[i] == 2 {
+			if res.err == nil {
+				t.Errorf("result %d has no error", i)
+			}
+			continue
+		}
+		if res.err != nil || res.v != ids[i]*10 {
+			t.Errorf("result %d is %v, %v, want %v", i, res.v, res.err, ids[i]*10)
+		}
+	}
+	if results[1] != results[3] {
+		t.Error("duplicate IDs do not share their result")
+	}
+}
+
+func TestRunBatchWorkers(t *testing.T) {
+	const workers = 3
+	var active, maxActive int32
+	full := make(chan struct{})
+	var once sync.Once
+
+	ids := make([]int, 20)
+	for i := range ids {
+		ids[i] = i
+	}
+	_, err := runBatch(context.Background(), ids, &BatchOptions{Workers: workers}, func(ctx context.Context, id int) (interface{}, *Response, error) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			m := atomic.LoadInt32(&maxActive)
+			if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+				break
+			}
+		}
+		// The first fetches wait for each other, so that the limit is
+		// reached.
+		if n == workers {
+			once.Do(func() { close(full) })
+		}
+		<-full
+		return nil, nil, nil
+	})
+	if err != nil {
+		t.Fatalf("runBatch returned error: %v", err)
+	}
+	if maxActive != workers {
+		t.Errorf("%d fetches ran concurrently, want %d", maxActive, workers)
+	}
+}
+
+func TestRunBatchCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var fetched []int
+	results, err := runBatch(ctx, []int{1, 2, 3, 2}, &BatchOptions{Workers: 1}, func(ctx context.Context, id int) (interface{}, *Response, error) {
+		mu.Lock()
+		fetched = append(fetched, id)
+		mu.Unlock()
+		if id == 1 {
+			cancel()
+			// Keep the only worker busy until the remaining IDs were
+			// skipped.
+			time.Sleep(50 * time.Millisecond)
+		}
+		return nil, nil, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("runBatch returned error %v, want %v", err, context.Canceled)
+	}
+	if want := []int{1}; !reflect.DeepEqual(fetched, want) {
+		t.Errorf("runBatch fetched %v, want %v", fetched, want)
+	}
+	for i, res := range results {
+		if !errors.Is(res.err, context.Canceled) {
+			t.Errorf("result %d (ID %d) has error %v, want %v", i, res.id, res.err, context.Canceled)
+		}
+	}
+}
+
+func TestRunBatchCanceledAfterQueued(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Both IDs are queued before ctx is canceled.
+	var started sync.WaitGroup
+	started.Add(2)
+	results, err := runBatch(ctx, []int{1, 2}, &BatchOptions{Workers: 2}, func(ctx context.Context, id int) (interface{}, *Response, error) {
+		started.Done()
+		started.Wait()
+		if id == 1 {
+			cancel()
+		}
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("runBatch returned error %v, want %v", err, context.Canceled)
+	}
+	for i, res := range results {
+		if !errors.Is(res.err, context.Canceled) {
+			t.Errorf("result %d (ID %d) has error %v, want %v", i, res.id, res.err, context.Canceled)
+		}
+	}
+}
+
+func TestRunBatchNilContext(t *testing.T) {
+	if _, err := runBatch(nil, []int{1}, nil, nil); err != errNonNilContext {
+		t.Errorf("runBatch returned error %v, want %v", err, errNonNilContext)
+	}
+}
+
+func TestGetMany(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/resources/")
+		if id == "2" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"id": %s}`, id)
+	}))
+	defer server.Close()
+
+	client, err := New(WithBaseURL(server.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := client.Resources.GetMany(context.Background(), []int{3, 2, 1, 3}, &BatchOptions{Workers: 2})
+	if err != nil {
+		t.Fatalf("GetMany returned error: %v", err)
+	}
+
+	var got []string
+	for _, res := range results {
+		switch {
+		case res.Err != nil:
+			if !IsNotFound(res.Err) {
+				t.Errorf("result for %d has error %v, want not found", res.ID, res.Err)
+			}
+			got = append(got, fmt.Sprintf("%d:err", res.ID))
+		default:
+			got = append(got, fmt.Sprintf("%d:%d", res.ID, res.Resource.ID))
+		}
+	}
+	if want := []string{"3:3", "2:err", "1:1", "3:3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMany returned %v, want %v", got, want)
+	}
+}
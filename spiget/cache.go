@@ -0,0 +1,92 @@
+package spiget
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+	"time"
+)
+
+// Cache stores the raw responses of GET requests, keyed by URL.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+}
+
+// NewMemoryCache returns a Cache keeping responses in memory for ttl.
+func NewMemoryCache(ttl time.Duration) Cache {
+	return &memoryCache{ttl: ttl, entries: make(map[string]memoryCacheEntry)}
+}
+
+type memoryCacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+type memoryCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+func (m *memoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (m *memoryCache) Set(key string, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = memoryCacheEntry{value: value, expires: time.Now().Add(m.ttl)}
+}
+
+// cacheMiddleware returns the Middleware answering GET requests from cache
+// and storing successful responses in it. Downloads are never cached.
+func cacheMiddleware(cache Cache) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *http.Request) (*Response, error) {
+			switch EndpointOf(req).Operation {
+			case "", "Resources.Download", "Resources.DownloadVersion":
+				return next.Do(ctx, req)
+			}
+			if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+				return next.Do(ctx, req)
+			}
+
+			key := req.URL.String()
+			if data, ok := cache.Get(key); ok {
+				resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+				if err == nil {
+					resp.Header.Set("X-From-Cache", "1")
+					return newResponse(resp), nil
+				}
+			}
+
+			resp, err := next.Do(ctx, req)
+			if err != nil || resp.StatusCode != http.StatusOK {
+				return resp, err
+			}
+			// DumpResponse reads the body and replaces it with a copy.
+			if data, err := httputil.DumpResponse(resp.Response, true); err == nil {
+				cache.Set(key, data)
+			}
+			return resp, nil
+		})
+	}
+}
@@ -15,6 +15,8 @@ type CategoriesService service
 type Category struct {
 	ID   int    `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
+
+	present fieldMask // fields present in the decoded JSON object
 }
 
 func (c *Category) String() string {
@@ -27,6 +29,10 @@ type CategoryListOptions struct {
 	ListOptions
 }
 
+func (o CategoryListOptions) validate() error {
+	return categoryFields.validate("category", o.Fields)
+}
+
 // Get a list of categories.
 //
 // Spiget API docs: https://spiget.org/documentation/#!/categories/get_categories
@@ -69,3 +75,27 @@ func (c *CategoriesService) Get(ctx context.Context, id int) (*Category, *Respon
 
 	return category, resp, nil
 }
+
+// Get the resources in a category.
+//
+// Spiget API docs: https://spiget.org/documentation/#!/categories/get_categories_category_resources
+func (c *CategoriesService) ListResources(ctx context.Context, id int, opts *ResourceListOptions) ([]*Resource, *Response, error) {
+	u := "categories/" + strconv.Itoa(id) + "/resources"
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := c.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resources []*Resource
+	resp, err := c.client.Do(ctx, req, &resources)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return resources, resp, nil
+}
@@ -0,0 +1,435 @@
+package spiget
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DecodedDescription returns the description of the update, which Spiget
+// encodes as base64 HTML.
+func (u *Update) DecodedDescription() (string, error) {
+	b, err := base64.StdEncoding.DecodeString(u.Description)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Time returns the date of the update.
+func (u *Update) Time() time.Time {
+	return time.Unix(int64(u.Date), 0)
+}
+
+// ChangelogFormat is the output format of Changelog.Render.
+type ChangelogFormat int
+
+// Formats supported by Changelog.Render.
+const (
+	ChangelogMarkdown ChangelogFormat = iota
+	ChangelogHTML
+	ChangelogText
+)
+
+// updateSlack is the time in seconds an update post may be published after
+// the version it announces.
+const updateSlack = 10 * 60
+
+// ChangelogEntry is an update post of a changelog.
+type ChangelogEntry struct {
+	Update *Update
+
+	// Description is the decoded HTML description of the update.
+	Description string
+}
+
+// Changelog collects the update posts published between two versions of a
+// resource.
+type Changelog struct {
+	Resource int
+	From, To *Version
+
+	// Entries are ordered from oldest to newest.
+	Entries []*ChangelogEntry
+}
+
+// Changelog returns the update posts published after the version from up to
+// and including the version to. Update posts are matched to versions by
+// their dates, as Spiget does not link them.
+func (r *ResourcesService) Changelog(ctx context.Context, id int, from, to int) (*Changelog, error) {
+	fromVersion, toVersion, err := r.findVersions(ctx, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if fromVersion.ReleaseDate > toVersion.ReleaseDate {
+		return nil, fmt.Errorf("%w: version %d was released after version %d", ErrInvalidRequest, from, to)
+	}
+	after := fromVersion.ReleaseDate + updateSlack
+	until := toVersion.ReleaseDate + updateSlack
+
+	changelog := &Changelog{Resource: id, From: fromVersion, To: toVersion}
+	opts := ListOptions{Size: 100, Page: 1, Sort: SortUpdatesBy(UpdateDate, Desc)}
+	for {
+		updates, resp, err := r.GetUpdates(ctx, id, opts)
+		if err != nil {
+			return nil, err
+		}
+		done := len(updates) == 0 || resp.NextPage > resp.LastPage
+		for _, u := range updates {
+			if u.Date <= after {
+				done = true
+				continue
+			}
+			if u.Date > until {
+				continue
+			}
+			desc, err := u.DecodedDescription()
+			if err != nil {
+				return nil, fmt.Errorf("update %d: %w", u.ID, err)
+			}
+			changelog.Entries = append(changelog.Entries, &ChangelogEntry{Update: u, Description: desc})
+		}
+		if done {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	sort.SliceStable(changelog.Entries, func(i, j int) bool {
+		return changelog.Entries[i].Update.Date < changelog.Entries[j].Update.Date
+	})
+	return changelog, nil
+}
+
+// findVersions pages through the versions of the resource until it found the
+// versions with the IDs from and to.
+func (r *ResourcesService) findVersions(ctx context.Context, id int, from, to int) (*Version, *Version, error) {
+	var fromVersion, toVersion *Version
+	opts := ListOptions{Size: 100, Page: 1}
+	for fromVersion == nil || toVersion == nil {
+		versions, resp, err := r.GetVersions(ctx, id, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, v := range versions {
+			if v.ID == from {
+				fromVersion = v
+			}
+			if v.ID == to {
+				toVersion = v
+			}
+		}
+		if len(versions) == 0 || resp.NextPage > resp.LastPage {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	switch {
+	case fromVersion == nil:
+		return nil, nil, fmt.Errorf("%w: resource %d has no version %d", ErrNotFound, id, from)
+	case toVersion == nil:
+		return nil, nil, fmt.Errorf("%w: resource %d has no version %d", ErrNotFound, id, to)
+	}
+	return fromVersion, toVersion, nil
+}
+
+// Render renders the changelog in the format. HTML descriptions are reduced
+// to an allowlist of formatting tags, and links other than http and https
+// links are dropped in all formats.
+func (c *Changelog) Render(format ChangelogFormat) (string, error) {
+	var b strings.Builder
+	title := "Changes from " + c.From.Name + " to " + c.To.Name
+
+	switch format {
+	case ChangelogMarkdown:
+		fmt.Fprintf(&b, "# %s\n", escapeMarkdown(title))
+		for _, e := range c.Entries {
+			fmt.Fprintf(&b, "\n## %s (%s)\n\n%s\n", escapeMarkdown(e.Update.Title), e.date(), htmlToText(e.Description, true))
+		}
+	case ChangelogHTML:
+		fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(title))
+		for _, e := range c.Entries {
+			fmt.Fprintf(&b, "<h2>%s <small>%s</small></h2>\n<div>%s</div>\n", html.EscapeString(e.Update.Title), e.date(), sanitizeHTML(e.Description))
+		}
+	case ChangelogText:
+		fmt.Fprintf(&b, "%s\n", title)
+		for _, e := range c.Entries {
+			fmt.Fprintf(&b, "\n%s (%s)\n\n%s\n", e.Update.Title, e.date(), htmlToText(e.Description, false))
+		}
+	default:
+		return "", fmt.Errorf("unknown changelog format %d", format)
+	}
+	return b.String(), nil
+}
+
+func (e *ChangelogEntry) date() string {
+	return e.Update.Time().UTC().Format("2006-01-02")
+}
+
+var (
+	htmlTagRE     = regexp.MustCompile(`<(/?)([a-zA-Z0-9]+)([^>]*)>`)
+	htmlAttrRE    = regexp.MustCompile(`([a-zA-Z-]+)\s*=\s*("[^"]*"|'[^']*'|[^\s>]+)`)
+	spaceRE       = regexp.MustCompile(`[ \t\r\n]+`)
+	blankLinesRE  = regexp.MustCompile(`\n{3,}`)
+	markdownChars = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "#", `\#`,
+		"&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+// escapeMarkdown escapes s for use as Markdown text. HTML special characters
+// are escaped as entities, as Markdown renderers pass HTML through.
+func escapeMarkdown(s string) string {
+	return markdownChars.Replace(s)
+}
+
+// safeURL returns raw if it is an absolute http or https URL, escaped for
+// use in Markdown and HTML attributes, or "" otherwise.
+func safeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return urlParens.Replace(u.String())
+}
+
+var urlParens = strings.NewReplacer("(", "%28", ")", "%29", " ", "%20")
+
+// allowedTags are the tags kept by sanitizeHTML. Void tags are true.
+var allowedTags = map[string]bool{
+	"a": false, "b": false, "strong": false, "i": false, "em": false, "u": false,
+	"s": false, "strike": false, "del": false, "code": false, "pre": false,
+	"p": false, "div": false, "span": false, "blockquote": false,
+	"ul": false, "ol": false, "li": false,
+	"h1": false, "h2": false, "h3": false, "h4": false, "h5": false, "h6": false,
+	"br": true, "img": true,
+}
+
+// droppedContent are the tags whose content sanitizeHTML drops along with
+// the tag.
+var droppedContent = map[string]bool{"script": true, "style": true, "iframe": true, "object": true, "noscript": true}
+
+// sanitizeHTML reduces s to the tags in allowedTags. Their attributes are
+// dropped except for http and https URLs in the href of links and the src of
+// images, text is escaped and unclosed tags are closed.
+func sanitizeHTML(s string) string {
+	var b strings.Builder
+	var open []string // open tags, innermost last
+	skip := ""        // tag whose content is dropped
+
+	last := 0
+	for _, m := range htmlTagRE.FindAllStringSubmatchIndex(s, -1) {
+		if skip == "" {
+			b.WriteString(html.EscapeString(html.UnescapeString(s[last:m[0]])))
+		}
+		last = m[1]
+		closing := m[3] > m[2]
+		tag := strings.ToLower(s[m[4]:m[5]])
+		attrs := s[m[6]:m[7]]
+
+		if skip != "" {
+			if closing && tag == skip {
+				skip = ""
+			}
+			continue
+		}
+		if droppedContent[tag] && !closing {
+			skip = tag
+			continue
+		}
+		void, ok := allowedTags[tag]
+		if !ok {
+			continue
+		}
+
+		if closing {
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == tag {
+					for j := len(open) - 1; j >= i; j-- {
+						b.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+			continue
+		}
+
+		switch tag {
+		case "a":
+			b.WriteString("<a")
+			if href := safeURL(htmlAttr(attrs, "href")); href != "" {
+				b.WriteString(` href="` + html.EscapeString(href) + `" rel="nofollow noopener"`)
+			}
+			b.WriteString(">")
+		case "img":
+			src := safeURL(htmlAttr(attrs, "src"))
+			if src == "" {
+				continue
+			}
+			b.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + html.EscapeString(htmlAttr(attrs, "alt")) + `">`)
+		default:
+			b.WriteString("<" + tag + ">")
+		}
+		if !void {
+			open = append(open, tag)
+		}
+	}
+	if skip == "" {
+		b.WriteString(html.EscapeString(html.UnescapeString(s[last:])))
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+	return b.String()
+}
+
+// htmlAttr returns the value of the attribute in the attributes of a tag.
+func htmlAttr(attrs, name string) string {
+	for _, m := range htmlAttrRE.FindAllStringSubmatch(attrs, -1) {
+		if strings.EqualFold(m[1], name) {
+			return html.UnescapeString(strings.Trim(m[2], `"'`))
+		}
+	}
+	return ""
+}
+
+// htmlToText converts the simple HTML of SpigotMC posts to Markdown or, if
+// markdown is false, plain text. Unknown tags are dropped.
+func htmlToText(s string, markdown bool) string {
+	var b strings.Builder
+	var links []string // safe hrefs of the open <a> tags
+	var lists []int    // counters of the open lists, -1 for unordered
+	inCode := false    // whether the text is in a code span
+	skip := ""         // tag whose content is dropped
+
+	mark := func(md string) {
+		if markdown {
+			b.WriteString(md)
+		}
+	}
+	text := func(t string) {
+		if skip != "" {
+			return
+		}
+		t = spaceRE.ReplaceAllString(html.UnescapeString(t), " ")
+		if markdown && !inCode {
+			t = escapeMarkdown(t)
+		}
+		b.WriteString(t)
+	}
+
+	last := 0
+	for _, m := range htmlTagRE.FindAllStringSubmatchIndex(s, -1) {
+		text(s[last:m[0]])
+		last = m[1]
+		closing := m[3] > m[2]
+		tag := strings.ToLower(s[m[4]:m[5]])
+		attrs := s[m[6]:m[7]]
+
+		if skip != "" {
+			if closing && tag == skip {
+				skip = ""
+			}
+			continue
+		}
+		if droppedContent[tag] && !closing {
+			skip = tag
+			continue
+		}
+
+		switch tag {
+		case "b", "strong":
+			mark("**")
+		case "i", "em":
+			mark("_")
+		case "s", "strike", "del":
+			mark("~~")
+		case "code":
+			if closing == inCode {
+				mark("`")
+				inCode = !closing
+			}
+		case "br":
+			b.WriteString("\n")
+		case "p", "div", "blockquote", "pre", "h1", "h2", "h3", "h4", "h5", "h6":
+			b.WriteString("\n\n")
+		case "ul", "ol":
+			if closing {
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+			} else if tag == "ol" {
+				lists = append(lists, 0)
+			} else {
+				lists = append(lists, -1)
+			}
+			b.WriteString("\n")
+		case "li":
+			if closing {
+				continue
+			}
+			b.WriteString("\n")
+			if len(lists) > 1 {
+				b.WriteString(strings.Repeat("  ", len(lists)-1))
+			}
+			if n := len(lists); n > 0 && lists[n-1] >= 0 {
+				lists[n-1]++
+				fmt.Fprintf(&b, "%d. ", lists[n-1])
+			} else {
+				b.WriteString("- ")
+			}
+		case "a":
+			// Links with unsafe URLs are reduced to their text.
+			if !closing {
+				href := safeURL(htmlAttr(attrs, "href"))
+				links = append(links, href)
+				if href != "" {
+					mark("[")
+				}
+				continue
+			}
+			if len(links) == 0 {
+				continue
+			}
+			href := links[len(links)-1]
+			links = links[:len(links)-1]
+			if href == "" {
+				continue
+			}
+			if markdown {
+				b.WriteString("](" + href + ")")
+			} else {
+				b.WriteString(" (" + href + ")")
+			}
+		case "img":
+			if src := safeURL(htmlAttr(attrs, "src")); markdown && src != "" {
+				b.WriteString("![" + escapeMarkdown(htmlAttr(attrs, "alt")) + "](" + src + ")")
+			}
+		}
+	}
+	text(s[last:])
+
+	lines := strings.Split(b.String(), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+		if !strings.HasPrefix(strings.TrimLeft(l, " "), "- ") && !startsWithNumber(strings.TrimLeft(l, " ")) {
+			lines[i] = strings.TrimSpace(l)
+		}
+	}
+	return strings.TrimSpace(blankLinesRE.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// startsWithNumber reports whether l starts with an ordered list marker.
+func startsWithNumber(l string) bool {
+	i := 0
+	for i < len(l) && l[i] >= '0' && l[i] <= '9' {
+		i++
+	}
+	return i > 0 && strings.HasPrefix(l[i:], ". ")
+}
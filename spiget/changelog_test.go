@@ -0,0 +1,117 @@
+package spiget
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChangelog(t *testing.T) {
+	desc := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/resources/1/versions":
+			// Two pages of versions.
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Page-Count", "2")
+				w.Header().Set("X-Page-Index", "1")
+				fmt.Fprint(w, `[{"id": 10, "name": "1.0", "releaseDate": 1000}, {"id": 20, "name": "2.0", "releaseDate": 200000}]`)
+				return
+			}
+			w.Header().Set("X-Page-Count", "2")
+			w.Header().Set("X-Page-Index", "2")
+			fmt.Fprint(w, `[{"id": 30, "name": "3.0", "releaseDate": 300000}]`)
+		case "/resources/1/updates":
+			fmt.Fprintf(w, `[
+				{"id": 4, "title": "4.0", "date": 400000, "description": %q},
+				{"id": 3, "title": "3.0", "date": 300030, "description": %q},
+				{"id": 2, "title": "2.0", "date": 200000, "description": %q},
+				{"id": 1, "title": "1.0", "date": 1000, "description": %q}
+			]`, desc("four"), desc(`<b>Fixed</b> <a href="javascript:alert(1)">this</a>`), desc(`<a href="https://example.com/">Docs</a> <code>a_b*</code>`), desc("one"))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(WithBaseURL(server.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changelog, err := client.Resources.Changelog(context.Background(), 1, 10, 30)
+	if err != nil {
+		t.Fatalf("Changelog returned error: %v", err)
+	}
+
+	tests := []struct {
+		format ChangelogFormat
+		want   string
+	}{
+		{ChangelogMarkdown, "# Changes from 1.0 to 3.0\n\n## 2.0 (1970-01-03)\n\n[Docs](https://example.com/) `a_b*`\n\n## 3.0 (1970-01-04)\n\n**Fixed** this\n"},
+		{ChangelogHTML, "<h1>Changes from 1.0 to 3.0</h1>\n<h2>2.0 <small>1970-01-03</small></h2>\n<div><a href=\"https://example.com/\" rel=\"nofollow noopener\">Docs</a> <code>a_b*</code></div>\n<h2>3.0 <small>1970-01-04</small></h2>\n<div><b>Fixed</b> <a>this</a></div>\n"},
+		{ChangelogText, "Changes from 1.0 to 3.0\n\n2.0 (1970-01-03)\n\nDocs (https://example.com/) a_b*\n\n3.0 (1970-01-04)\n\nFixed this\n"},
+	}
+	for _, tt := range tests {
+		got, err := changelog.Render(tt.format)
+		if err != nil {
+			t.Errorf("Render(%d) returned error: %v", tt.format, err)
+		}
+		if got != tt.want {
+			t.Errorf("Render(%d) returned\n%q, want\n%q", tt.format, got, tt.want)
+		}
+	}
+
+	if _, err := client.Resources.Changelog(context.Background(), 1, 30, 10); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Changelog of reversed versions returned error %v, want %v", err, ErrInvalidRequest)
+	}
+	if _, err := client.Resources.Changelog(context.Background(), 1, 10, 99); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Changelog of missing version returned error %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`<b onclick="x()">bold</b>`, `<b>bold</b>`},
+		{`<script>alert(1)</script>text`, `text`},
+		{`<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{`<a href="https://a.com/?q=1&amp;r=2">x</a>`, `<a href="https://a.com/?q=1&amp;r=2" rel="nofollow noopener">x</a>`},
+		{`<img src="data:image/png;base64,AA" onerror="x()">`, ``},
+		{`<img src="https://i.imgur.com/a.png" alt="a&quot;b">`, `<img src="https://i.imgur.com/a.png" alt="a&#34;b">`},
+		{`<iframe src="https://evil"></iframe><p>ok`, `<p>ok</p>`},
+		{`</div><div>x`, `<div>x</div>`},
+		{`<ul><li>a<li>b</ul>`, `<ul><li>a<li>b</li></li></ul>`},
+		{`1 < 2 &amp; <x-custom>`, `1 &lt; 2 &amp; `},
+		{`<img src=x onerror=alert(1)//`, `&lt;img src=x onerror=alert(1)//`},
+	}
+	for _, tt := range tests {
+		if got := sanitizeHTML(tt.in); got != tt.want {
+			t.Errorf("sanitizeHTML(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLToMarkdown(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`<a href="javascript:alert(1)">click</a>`, `click`},
+		{`<a href="https://a.com/x)y">link</a>`, `[link](https://a.com/x%29y)`},
+		{`<code>*_[x]</code> *_`, "`*_[x]` \\*\\_"},
+		{`<img src="javascript:x" alt="a">`, ``},
+		{`<ol><li>one</li><li>two</li></ol>`, "1. one\n2. two"},
+		{`<p>Fixed &lt;img src=x onerror=alert(1)&gt; bug</p>`, `Fixed &lt;img src=x onerror=alert(1)&gt; bug`},
+		{`a &amp;lt; b`, `a &amp;lt; b`},
+	}
+	for _, tt := range tests {
+		if got := htmlToText(tt.in, true); got != tt.want {
+			t.Errorf("htmlToText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package spiget
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DecodeMode controls how JSON response bodies are decoded by Client.Do.
+type DecodeMode int
+
+const (
+	// DecodeDefault decodes responses with encoding/json. A single field with
+	// an unexpected type fails the whole decode.
+	DecodeDefault DecodeMode = iota
+
+	// DecodeLenient coerces common type mismatches, such as numbers encoded
+	// as strings or non-string values in string maps, before decoding.
+	// Values which cannot be coerced are dropped. Every coerced or dropped
+	// value is reported in Response.Warnings instead of failing the decode.
+	DecodeLenient
+
+	// DecodeStrict fails the decode if the response contains fields unknown
+	// to the target type. It is intended for tests detecting API changes.
+	DecodeStrict
+)
+
+// DecodeWarning describes a value in a response body which did not match the
+// type it was decoded into when using DecodeLenient.
+type DecodeWarning struct {
+	Path    string // path of the value, e.g. "[3].file.size"
+	Message string // what was done with the value
+}
+
+func (w DecodeWarning) String() string {
+	return w.Path + ": " + w.Message
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// decode decodes the JSON in r into v according to mode. Warnings produced
+// by lenient decoding are returned.
+func decode(mode DecodeMode, r io.Reader, v interface{}) ([]DecodeWarning, error) {
+	if mode == DecodeDefault {
+		err := json.NewDecoder(r).Decode(v)
+		if err == io.EOF {
+			err = nil // ignore EOF errors caused by empty response body
+		}
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	w := &decodeWalker{mode: mode}
+	raw, _ = w.walk("", reflect.TypeOf(v), raw)
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	if mode == DecodeLenient {
+		data, err = json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(w.warnings, func(i, j int) bool { return w.warnings[i].Path < w.warnings[j].Path })
+
+	dec = json.NewDecoder(bytes.NewReader(data))
+	if mode == DecodeStrict {
+		dec.DisallowUnknownFields()
+	}
+	return w.warnings, dec.Decode(v)
+}
+
+// decodeWalker walks a generically decoded JSON value alongside the Go type
+// it will be decoded into. In lenient mode mismatching values are coerced or
+// dropped, in strict mode the first unknown object key is reported.
+//
+// Unknown keys have to be detected by the walker because
+// json.Decoder.DisallowUnknownFields does not apply to the json.Unmarshal
+// calls made by UnmarshalJSON methods of nested models.
+type decodeWalker struct {
+	mode     DecodeMode
+	warnings []DecodeWarning
+	err      error
+}
+
+func (w *decodeWalker) warn(path, format string, args ...interface{}) {
+	if path == "" {
+		path = "."
+	}
+	w.warnings = append(w.warnings, DecodeWarning{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// walk returns the value to decode in place of v, and false if v should be
+// dropped instead.
+func (w *decodeWalker) walk(path string, t reflect.Type, v interface{}) (interface{}, bool) {
+	if v == nil || w.err != nil {
+		return v, true
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// Types with custom decoding are left alone, except for models whose
+	// UnmarshalJSON decodes the struct fields as usual.
+	obj, isObj := v.(map[string]interface{})
+	if t == timestampType || (t.Kind() != reflect.Struct && reflect.PtrTo(t).Implements(unmarshalerType)) {
+		return v, true
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return v, true
+	case reflect.Struct:
+		if !isObj {
+			return w.mismatch(path, t, v)
+		}
+		for key, value := range obj {
+			sf, ok := jsonField(t, key)
+			if !ok {
+				if w.mode == DecodeStrict {
+					w.err = fmt.Errorf("json: unknown field %q", joinPath(path, key))
+					return v, true
+				}
+				continue
+			}
+			if value, ok := w.walk(joinPath(path, key), sf.Type, value); ok {
+				obj[key] = value
+			} else {
+				delete(obj, key)
+			}
+		}
+		return obj, true
+	case reflect.Map:
+		if !isObj {
+			return w.mismatch(path, t, v)
+		}
+		for key, value := range obj {
+			if value, ok := w.walk(joinPath(path, key), t.Elem(), value); ok {
+				obj[key] = value
+			} else {
+				delete(obj, key)
+			}
+		}
+		return obj, true
+	case reflect.Slice, reflect.Array:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return w.mismatch(path, t, v)
+		}
+		kept := arr[:0]
+		for i, elem := range arr {
+			if elem, ok := w.walk(fmt.Sprintf("%s[%d]", path, i), t.Elem(), elem); ok {
+				kept = append(kept, elem)
+			}
+		}
+		return kept, true
+	}
+
+	if w.mode != DecodeLenient {
+		return v, true
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		switch v := v.(type) {
+		case string:
+			return v, true
+		case json.Number:
+			w.warn(path, "coerced number to string")
+			return v.String(), true
+		case bool:
+			w.warn(path, "coerced bool to string")
+			return strconv.FormatBool(v), true
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := v.(json.Number); ok {
+			if _, err := n.Int64(); err == nil {
+				return v, true
+			}
+		}
+		if f, ok := w.number(path, v); ok {
+			if f != math.Trunc(f) {
+				w.warn(path, "truncated %v to integer", f)
+			}
+			return json.Number(strconv.FormatInt(int64(f), 10)), true
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, ok := w.number(path, v); ok {
+			return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), true
+		}
+	case reflect.Bool:
+		switch v := v.(type) {
+		case bool:
+			return v, true
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				w.warn(path, "coerced string to bool")
+				return b, true
+			}
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				w.warn(path, "coerced number to bool")
+				return f != 0, true
+			}
+		}
+	default:
+		return v, true
+	}
+	return w.mismatch(path, t, v)
+}
+
+// number returns v as a float64 if it is a JSON number or a string containing
+// a number.
+func (w *decodeWalker) number(path string, v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err == nil {
+			w.warn(path, "coerced string to number")
+		}
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// mismatch drops v in lenient mode. Otherwise v is kept, leaving it to the
+// json package to report the type error.
+func (w *decodeWalker) mismatch(path string, t reflect.Type, v interface{}) (interface{}, bool) {
+	if w.mode != DecodeLenient {
+		return v, true
+	}
+	w.warn(path, "dropped %s value, want %s", jsonKind(v), t.Kind())
+	return nil, false
+}
+
+// jsonField returns the field of struct type t which the JSON object key
+// name is decoded into, matching names the same way encoding/json does.
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	var fold *reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tagName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if tagName == "-" {
+			continue
+		}
+		if tagName == "" {
+			tagName = sf.Name
+		}
+		if tagName == name {
+			return sf, true
+		}
+		if fold == nil && strings.EqualFold(tagName, name) {
+			fold = &sf
+		}
+	}
+	if fold != nil {
+		return *fold, true
+	}
+	return reflect.StructField{}, false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	}
+	return "null"
+}
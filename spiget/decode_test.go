@@ -0,0 +1,122 @@
+package spiget
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeLenient(t *testing.T) {
+	body := `[{
+		"id": "42",
+		"name": 1.5,
+		"tag": true,
+		"likes": "7",
+		"downloads": 12.7,
+		"premium": "true",
+		"rating": {"count": "3", "average": "4.5"},
+		"links": {"discord": "https://discord.gg/x", "count": 3},
+		"testedVersions": ["1.20", 1.19],
+		"author": {"id": {"nested": true}, "bar": 1}
+	}]`
+
+	var resources []*Resource
+	warnings, err := decode(DecodeLenient, strings.NewReader(body), &resources)
+	if err != nil {
+		t.Fatalf("decode returned error: %v", err)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("decode returned %d resources, want 1", len(resources))
+	}
+	r := resources[0]
+	if r.ID != 42 || r.Likes != 7 || r.Downloads != 12 || !r.Premium {
+		t.Errorf("numbers and bools were not coerced: %+v", r)
+	}
+	if r.Name != "1.5" || r.Tag != "true" {
+		t.Errorf("strings were not coerced: name %q, tag %q", r.Name, r.Tag)
+	}
+	if r.Rating.Count != 3 || r.Rating.Average != 4.5 {
+		t.Errorf("nested numbers were not coerced: %+v", r.Rating)
+	}
+	if want := map[string]string{"discord": "https://discord.gg/x", "count": "3"}; !reflect.DeepEqual(r.Links, want) {
+		t.Errorf("Links is %v, want %v", r.Links, want)
+	}
+	if want := []string{"1.20", "1.19"}; !reflect.DeepEqual(r.TestedVersions, want) {
+		t.Errorf("TestedVersions is %v, want %v", r.TestedVersions, want)
+	}
+	if r.Author.ID != 0 {
+		t.Errorf("Author.ID is %d, want the object to be dropped", r.Author.ID)
+	}
+
+	want := []DecodeWarning{
+		{"[0].author.id", "dropped object value, want int"},
+		{"[0].downloads", "truncated 12.7 to integer"},
+		{"[0].id", "coerced string to number"},
+		{"[0].likes", "coerced string to number"},
+		{"[0].links.count", "coerced number to string"},
+		{"[0].name", "coerced number to string"},
+		{"[0].premium", "coerced string to bool"},
+		{"[0].rating.average", "coerced string to number"},
+		{"[0].rating.count", "coerced string to number"},
+		{"[0].tag", "coerced bool to string"},
+		{"[0].testedVersions[1]", "coerced number to string"},
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("decode returned warnings\n%v\nwant\n%v", warnings, want)
+	}
+}
+
+func TestDecodeLenientDropsInvalid(t *testing.T) {
+	var r *Resource
+	warnings, err := decode(DecodeLenient, strings.NewReader(`{"id": 1, "likes": "many", "testedVersions": "1.20"}`), &r)
+	if err != nil {
+		t.Fatalf("decode returned error: %v", err)
+	}
+	if r.ID != 1 || r.Likes != 0 || r.TestedVersions != nil {
+		t.Errorf("decode returned %+v, want invalid values dropped", r)
+	}
+	want := []DecodeWarning{
+		{"likes", "dropped string value, want int"},
+		{"testedVersions", "dropped string value, want slice"},
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("decode returned warnings %v, want %v", warnings, want)
+	}
+}
+
+func TestDecodeStrict(t *testing.T) {
+	tests := []struct {
+		body    string
+		unknown string
+	}{
+		{`{"id": 1, "foo": 2}`, "foo"},
+		{`[{"id": 1}, {"id": 2, "author": {"id": 3, "bar": 4}}]`, "[1].author.bar"},
+		{`[{"author": {"bar": 1}}]`, "[0].author.bar"},
+		{`{"versions": [{"id": 1, "baz": true}]}`, "versions[0].baz"},
+	}
+	for _, tt := range tests {
+		target := reflect.New(reflect.TypeOf([]*Resource{}))
+		if !strings.HasPrefix(tt.body, "[") {
+			target = reflect.New(reflect.TypeOf(&Resource{}))
+		}
+		_, err := decode(DecodeStrict, strings.NewReader(tt.body), target.Interface())
+		if err == nil || !strings.Contains(err.Error(), `"`+tt.unknown+`"`) {
+			t.Errorf("decode(%s) returned error %v, want unknown field %q", tt.body, err, tt.unknown)
+		}
+	}
+
+	var r *Resource
+	if _, err := decode(DecodeStrict, strings.NewReader(`{"id": 1, "author": {"id": 2}}`), &r); err != nil {
+		t.Errorf("decode of known fields returned error: %v", err)
+	}
+}
+
+func TestDecodeDefaultTypeError(t *testing.T) {
+	var r *Resource
+	if _, err := decode(DecodeDefault, strings.NewReader(`{"id": "1"}`), &r); err == nil {
+		t.Error("decode of a mismatching type returned no error")
+	}
+	if _, err := decode(DecodeDefault, strings.NewReader(""), &r); err != nil {
+		t.Errorf("decode of an empty body returned error: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package spiget
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Endpoint describes the API method a request was created by. It allows
+// instrumentation wrapping the http.Client, such as tracing transports, to
+// name and annotate requests without knowing the URL layout of the API.
+type Endpoint struct {
+	// Operation is the service method, e.g. "Resources.Get". It is empty
+	// for requests not matching any endpoint, such as redirects to the
+	// download CDN.
+	Operation string
+
+	// Params holds the path parameters of the request, keyed by the names
+	// "resource", "version", "versions", "author", "category", "query" and
+	// "webhook".
+	Params map[string]string
+}
+
+// route maps a path template to the operation it belongs to. Templates are
+// matched against the end of the request path, so they work regardless of
+// the path of the BaseURL.
+type route struct {
+	method    string
+	template  []string
+	operation string
+}
+
+// routes is ordered such that literal segments win over parameters.
+var routes = []route{
+	{"GET", []string{"search", "resources", "{query}"}, "Resources.Search"},
+	{"GET", []string{"search", "authors", "{query}"}, "Authors.Search"},
+	{"GET", []string{"resources", "for", "{versions}"}, "Resources.ListByVersions"},
+	{"GET", []string{"resources", "free"}, "Resources.ListFree"},
+	{"GET", []string{"resources", "new"}, "Resources.ListNew"},
+	{"GET", []string{"resources", "premium"}, "Resources.ListPremium"},
+	{"GET", []string{"resources", "{resource}", "author"}, "Resources.GetAuthor"},
+	{"GET", []string{"resources", "{resource}", "download"}, "Resources.Download"},
+	{"GET", []string{"resources", "{resource}", "reviews"}, "Resources.GetReviews"},
+	{"GET", []string{"resources", "{resource}", "updates", "latest"}, "Resources.GetLatestUpdate"},
+	{"GET", []string{"resources", "{resource}", "updates"}, "Resources.GetUpdates"},
+	{"GET", []string{"resources", "{resource}", "versions", "latest"}, "Resources.GetLatestVersion"},
+	{"GET", []string{"resources", "{resource}", "versions", "{version}", "download"}, "Resources.DownloadVersion"},
+	{"GET", []string{"resources", "{resource}", "versions", "{version}"}, "Resources.GetVersion"},
+	{"GET", []string{"resources", "{resource}", "versions"}, "Resources.GetVersions"},
+	{"GET", []string{"resources", "{resource}"}, "Resources.Get"},
+	{"GET", []string{"resources", ""}, "Resources.List"},
+	{"GET", []string{"authors", "{author}", "resources"}, "Authors.ListResources"},
+	{"GET", []string{"authors", "{author}"}, "Authors.Get"},
+	{"GET", []string{"authors"}, "Authors.List"},
+	{"GET", []string{"categories", "{category}", "resources"}, "Categories.ListResources"},
+	{"GET", []string{"categories", "{category}"}, "Categories.Get"},
+	{"GET", []string{"categories"}, "Categories.List"},
+	{"GET", []string{"status"}, "Status.Get"},
+	{"DELETE", []string{"webhook", "delete", "{webhook}", "{secret}"}, "Webhook.Delete"},
+	{"GET", []string{"webhook", "events"}, "Webhook.GetEvents"},
+	{"POST", []string{"webhook", "register"}, "Webhook.Register"},
+	{"GET", []string{"webhook", "status", "{webhook}"}, "Webhook.GetStatus"},
+}
+
+// EndpointOf returns the Endpoint of a request created by one of the
+// services of a Client. The webhook secret is never included in Params.
+func EndpointOf(req *http.Request) Endpoint {
+	if req == nil || req.URL == nil {
+		return Endpoint{}
+	}
+	segments := strings.Split(req.URL.EscapedPath(), "/")
+
+	for _, r := range routes {
+		if r.method != req.Method || len(segments) < len(r.template)+1 {
+			continue
+		}
+		tail := segments[len(segments)-len(r.template):]
+		params, ok := matchRoute(r.template, tail)
+		if ok {
+			return Endpoint{Operation: r.operation, Params: params}
+		}
+	}
+	return Endpoint{}
+}
+
+// matchRoute matches the path segments against template and returns the
+// unescaped parameters.
+func matchRoute(template, segments []string) (map[string]string, bool) {
+	var params map[string]string
+	for i, t := range template {
+		seg := segments[i]
+		if !strings.HasPrefix(t, "{") {
+			if seg != t {
+				return nil, false
+			}
+			continue
+		}
+		if seg == "" {
+			return nil, false
+		}
+		name := strings.Trim(t, "{}")
+		if name == "secret" {
+			continue
+		}
+		value, err := url.PathUnescape(seg)
+		if err != nil {
+			return nil, false
+		}
+		if params == nil {
+			params = make(map[string]string)
+		}
+		params[name] = value
+	}
+	return params, true
+}
@@ -0,0 +1,69 @@
+package spiget
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestEndpointOf(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         Endpoint
+	}{
+		{"GET", "search/resources/world%20edit?field=name", Endpoint{"Resources.Search", map[string]string{"query": "world edit"}}},
+		{"GET", "search/authors/md_5", Endpoint{"Authors.Search", map[string]string{"query": "md_5"}}},
+		{"GET", "resources/for/1.20,1.21?method=any", Endpoint{"Resources.ListByVersions", map[string]string{"versions": "1.20,1.21"}}},
+		{"GET", "resources/free", Endpoint{"Resources.ListFree", nil}},
+		{"GET", "resources/new", Endpoint{"Resources.ListNew", nil}},
+		{"GET", "resources/premium", Endpoint{"Resources.ListPremium", nil}},
+		{"GET", "resources/1/author", Endpoint{"Resources.GetAuthor", map[string]string{"resource": "1"}}},
+		{"GET", "resources/1/download", Endpoint{"Resources.Download", map[string]string{"resource": "1"}}},
+		{"GET", "resources/1/reviews?size=10&page=2", Endpoint{"Resources.GetReviews", map[string]string{"resource": "1"}}},
+		{"GET", "resources/1/updates/latest", Endpoint{"Resources.GetLatestUpdate", map[string]string{"resource": "1"}}},
+		{"GET", "resources/1/updates", Endpoint{"Resources.GetUpdates", map[string]string{"resource": "1"}}},
+		{"GET", "resources/1/versions/latest", Endpoint{"Resources.GetLatestVersion", map[string]string{"resource": "1"}}},
+		{"GET", "resources/1/versions/2/download", Endpoint{"Resources.DownloadVersion", map[string]string{"resource": "1", "version": "2"}}},
+		{"GET", "resources/1/versions/2", Endpoint{"Resources.GetVersion", map[string]string{"resource": "1", "version": "2"}}},
+		{"GET", "resources/1/versions", Endpoint{"Resources.GetVersions", map[string]string{"resource": "1"}}},
+		{"GET", "resources/1", Endpoint{"Resources.Get", map[string]string{"resource": "1"}}},
+		{"GET", "resources/?size=10", Endpoint{"Resources.List", nil}},
+		{"GET", "authors/2/resources", Endpoint{"Authors.ListResources", map[string]string{"author": "2"}}},
+		{"GET", "authors/2", Endpoint{"Authors.Get", map[string]string{"author": "2"}}},
+		{"GET", "authors", Endpoint{"Authors.List", nil}},
+		{"GET", "categories/3/resources", Endpoint{"Categories.ListResources", map[string]string{"category": "3"}}},
+		{"GET", "categories/3", Endpoint{"Categories.Get", map[string]string{"category": "3"}}},
+		{"GET", "categories", Endpoint{"Categories.List", nil}},
+		{"GET", "status", Endpoint{"Status.Get", nil}},
+		{"DELETE", "webhook/delete/a%2Fb/s%2Fecret", Endpoint{"Webhook.Delete", map[string]string{"webhook": "a/b"}}},
+		{"GET", "webhook/events", Endpoint{"Webhook.GetEvents", nil}},
+		{"POST", "webhook/register", Endpoint{"Webhook.Register", nil}},
+		{"GET", "webhook/status/abc", Endpoint{"Webhook.GetStatus", map[string]string{"webhook": "abc"}}},
+
+		// Unknown methods and paths.
+		{"POST", "resources/1", Endpoint{}},
+		{"GET", "resources/1/unknown", Endpoint{}},
+		{"GET", "webhook/delete/a/secret", Endpoint{}},
+	}
+	for _, base := range []string{"https://api.spiget.org/v2/", "https://mirror.example.com/spiget/api/v2/"} {
+		for _, tt := range tests {
+			req, err := http.NewRequest(tt.method, base+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := EndpointOf(req); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EndpointOf(%s %s) returned %+v, want %+v", tt.method, req.URL, got, tt.want)
+			}
+		}
+	}
+
+	for _, raw := range []string{"https://cdn.spiget.org/file/spiget-resources/1.jar", "https://api.spiget.org/"} {
+		req, _ := http.NewRequest("GET", raw, nil)
+		if got := EndpointOf(req); !reflect.DeepEqual(got, Endpoint{}) {
+			t.Errorf("EndpointOf(GET %s) returned %+v, want none", raw, got)
+		}
+	}
+	if got := EndpointOf(nil); !reflect.DeepEqual(got, Endpoint{}) {
+		t.Errorf("EndpointOf(nil) returned %+v, want none", got)
+	}
+}
@@ -0,0 +1,291 @@
+package spiget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BackendHealth is the health of a backend of a client created with
+// WithFailover. Lower values are healthier.
+type BackendHealth int
+
+const (
+	// BackendHealthy is a backend whose fetch is active and up to date.
+	BackendHealthy BackendHealth = iota
+
+	// BackendDegraded is a reachable backend which is not fetching from
+	// SpigotMC, or whose last fetch ended longer than MaxFetchAge ago. Its
+	// data may be outdated.
+	BackendDegraded
+
+	// BackendDown is a backend which failed its health check or a request.
+	BackendDown
+)
+
+func (h BackendHealth) String() string {
+	switch h {
+	case BackendHealthy:
+		return "healthy"
+	case BackendDegraded:
+		return "degraded"
+	case BackendDown:
+		return "down"
+	}
+	return fmt.Sprintf("BackendHealth(%d)", int(h))
+}
+
+// FailoverOptions specifies the optional parameters to WithFailover.
+type FailoverOptions struct {
+	// CheckInterval is the time between health checks of the backends.
+	// Defaults to one minute.
+	CheckInterval time.Duration
+
+	// MaxFetchAge is the age of the end of the last fetch after which a
+	// backend is considered degraded. Defaults to 24 hours.
+	MaxFetchAge time.Duration
+
+	// CheckTimeout limits the health check of each backend, so a hanging
+	// backend cannot stall the check. Defaults to ten seconds.
+	CheckTimeout time.Duration
+}
+
+// Backend is the state of a backend of a client created with WithFailover.
+type Backend struct {
+	URL     string
+	Health  BackendHealth
+	Checked time.Time // zero if the backend was not checked yet
+	Err     error     // the error of the last failed check or request
+}
+
+// WithFailover makes the client send requests to the healthiest of the
+// given base URLs, e.g. a self-hosted instance and the public API. Backends
+// are health-checked with StatusService.Get. Requests failing with a
+// connection error or a 5xx status are sent to the next backend; ties are
+// broken by the order of baseURLs.
+//
+// BaseURL is set to the first of baseURLs. Requests made for other URLs are
+// sent unmodified.
+func WithFailover(baseURLs []string, opts FailoverOptions) Option {
+	return func(c *Client) error {
+		if len(baseURLs) == 0 {
+			return errors.New("failover requires at least one base URL")
+		}
+		if opts.CheckInterval < 0 || opts.MaxFetchAge < 0 || opts.CheckTimeout < 0 {
+			return errors.New("failover options must not have negative values")
+		}
+		if opts.CheckInterval == 0 {
+			opts.CheckInterval = time.Minute
+		}
+		if opts.MaxFetchAge == 0 {
+			opts.MaxFetchAge = 24 * time.Hour
+		}
+		if opts.CheckTimeout == 0 {
+			opts.CheckTimeout = 10 * time.Second
+		}
+
+		f := &failover{opts: opts}
+		for _, baseURL := range baseURLs {
+			probe := &Client{}
+			if err := WithBaseURL(baseURL)(probe); err != nil {
+				return err
+			}
+			f.backends = append(f.backends, &backend{url: probe.BaseURL})
+		}
+		c.BaseURL = f.backends[0].url
+		c.failover = f
+		return nil
+	}
+}
+
+// Backends returns the state of the backends of a client created with
+// WithFailover, ordered from healthiest to least healthy. It returns nil for
+// other clients.
+func (c *Client) Backends() []Backend {
+	if c.failover == nil {
+		return nil
+	}
+	f := c.failover
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var backends []Backend
+	for _, b := range f.ordered() {
+		backends = append(backends, Backend{URL: b.url.String(), Health: b.health, Checked: b.checked, Err: b.err})
+	}
+	return backends
+}
+
+type backend struct {
+	url     *url.URL
+	health  BackendHealth
+	checked time.Time
+	err     error
+}
+
+// failover holds the backends of a client. It is shared by the copies made
+// with Client.With.
+type failover struct {
+	opts FailoverOptions
+
+	mu        sync.Mutex
+	backends  []*backend
+	lastCheck time.Time
+	checking  bool
+}
+
+// ordered returns the backends sorted by health. f.mu must be held.
+func (f *failover) ordered() []*backend {
+	backends := append([]*backend(nil), f.backends...)
+	sort.SliceStable(backends, func(i, j int) bool { return backends[i].health < backends[j].health })
+	return backends
+}
+
+// check health-checks all backends if the last check is older than the
+// CheckInterval. The backends are probed without holding f.mu, so requests
+// arriving meanwhile are sent using the previous health of the backends
+// instead of waiting for the check.
+func (f *failover) check(ctx context.Context, c *Client) {
+	f.mu.Lock()
+	if f.checking || time.Since(f.lastCheck) < f.opts.CheckInterval {
+		f.mu.Unlock()
+		return
+	}
+	f.checking = true
+	backends := append([]*backend(nil), f.backends...)
+	f.mu.Unlock()
+
+	type result struct {
+		health BackendHealth
+		err    error
+	}
+	results := make([]result, len(backends))
+	for i, b := range backends {
+		results[i].health, results[i].err = f.probe(ctx, c, b.url)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checking = false
+	if ctx.Err() != nil {
+		// The check was not completed and is repeated by the next request.
+		return
+	}
+	now := time.Now()
+	for i, b := range backends {
+		var limiterErr *rateLimiterError
+		if errors.As(results[i].err, &limiterErr) {
+			// The probe was not sent, so the health is unknown.
+			continue
+		}
+		b.health, b.err, b.checked = results[i].health, results[i].err, now
+	}
+	f.lastCheck = now
+}
+
+// probe requests the status of the backend at baseURL without failover,
+// retries or caching, and returns its health.
+func (f *failover) probe(ctx context.Context, c *Client, baseURL *url.URL) (BackendHealth, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.opts.CheckTimeout)
+	defer cancel()
+
+	probe, err := c.With(WithBaseURL(baseURL.String()))
+	if err != nil {
+		return BackendDown, err
+	}
+	probe.failover, probe.RetryPolicy, probe.Cache, probe.dataAge = nil, nil, nil, nil
+	status, _, err := probe.Status.Get(ctx)
+	if err != nil {
+		return BackendDown, err
+	}
+	return f.health(status), nil
+}
+
+// health interprets the status of a backend.
+func (f *failover) health(sr *StatusResponse) BackendHealth {
+	if sr == nil || sr.Status == nil {
+		return BackendDegraded
+	}
+	fetch := sr.Status.Fetch
+	if !fetch.Active || time.Since(fetchTime(fetch.End)) > f.opts.MaxFetchAge {
+		return BackendDegraded
+	}
+	return BackendHealthy
+}
+
+// markDown marks the backend as down after a failed request.
+func (f *failover) markDown(b *backend, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b.health = BackendDown
+	b.err = err
+	b.checked = time.Now()
+}
+
+// middleware returns the Middleware sending requests of c to its backends.
+func (f *failover) middleware(c *Client, next Doer) Doer {
+	return DoerFunc(func(ctx context.Context, req *http.Request) (*Response, error) {
+		prefix := c.BaseURL.String()
+		if !strings.HasPrefix(req.URL.String(), prefix) {
+			return next.Do(ctx, req)
+		}
+		rel := strings.TrimPrefix(req.URL.String(), prefix)
+
+		f.check(ctx, c)
+		f.mu.Lock()
+		backends := f.ordered()
+		f.mu.Unlock()
+
+		var (
+			resp *Response
+			err  error
+		)
+		for i, b := range backends {
+			u, parseErr := b.url.Parse(rel)
+			if parseErr != nil {
+				return nil, parseErr
+			}
+			r := req.Clone(ctx)
+			r.URL, r.Host = u, u.Host
+			if i > 0 && req.GetBody != nil {
+				if r.Body, err = req.GetBody(); err != nil {
+					return nil, err
+				}
+			}
+
+			resp, err = next.Do(ctx, r)
+			if !failoverable(ctx, err) {
+				return resp, err
+			}
+			f.markDown(b, err)
+			if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+				break
+			}
+		}
+		return resp, err
+	})
+}
+
+// failoverable reports whether a request failing with err should be sent to
+// the next backend.
+func failoverable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var errResp *ErrorResponse
+	var urlErr *url.Error
+	if errors.As(err, &errResp) || errors.As(err, &urlErr) {
+		return IsServerUnavailable(err)
+	}
+	var limiterErr *rateLimiterError
+	if errors.As(err, &limiterErr) {
+		return false
+	}
+	return !errors.Is(err, ErrInvalidRequest) && !errors.Is(err, errNonNilContext)
+}
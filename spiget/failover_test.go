@@ -0,0 +1,224 @@
+package spiget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeBackend is a Spiget API whose health can be changed while it is used.
+type fakeBackend struct {
+	*httptest.Server
+
+	name     string
+	fetching flag // whether the status reports an active fetch
+	failing  flag // whether requests other than the status fail
+	hang     chan struct{}
+}
+
+// flag is a bool safe for concurrent use.
+type flag struct {
+	v int32
+}
+
+func (f *flag) Store(v bool) {
+	var i int32
+	if v {
+		i = 1
+	}
+	atomic.StoreInt32(&f.v, i)
+}
+
+func (f *flag) Load() bool {
+	return atomic.LoadInt32(&f.v) == 1
+}
+
+func newFakeBackend(t *testing.T, name string) *fakeBackend {
+	b := &fakeBackend{name: name}
+	b.fetching.Store(true)
+	b.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/status" {
+			if b.hang != nil {
+				select {
+				case <-b.hang:
+				case <-r.Context().Done():
+				}
+				return
+			}
+			fmt.Fprintf(w, `{"status": {"fetch": {"active": %t, "end": %d}}}`, b.fetching.Load(), time.Now().UnixMilli())
+			return
+		}
+		if b.failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"name": %q}`, b.name)
+	}))
+	t.Cleanup(b.Close)
+	return b
+}
+
+func newFailoverClient(t *testing.T, opts FailoverOptions, backends ...*fakeBackend) *Client {
+	var urls []string
+	for _, b := range backends {
+		urls = append(urls, b.URL+"/")
+	}
+	client, err := New(WithFailover(urls, opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// getFrom returns the name of the backend serving a request.
+func getFrom(t *testing.T, client *Client) string {
+	t.Helper()
+	r, _, err := client.Resources.Get(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Resources.Get returned error: %v", err)
+	}
+	return r.Name
+}
+
+func backendHealth(client *Client) []BackendHealth {
+	var health []BackendHealth
+	for _, b := range client.Backends() {
+		health = append(health, b.Health)
+	}
+	return health
+}
+
+func TestFailover(t *testing.T) {
+	primary := newFakeBackend(t, "primary")
+	secondary := newFakeBackend(t, "secondary")
+	client := newFailoverClient(t, FailoverOptions{CheckInterval: time.Hour}, primary, secondary)
+
+	if got := getFrom(t, client); got != "primary" {
+		t.Errorf("request served by %s, want primary", got)
+	}
+
+	primary.failing.Store(true)
+	if got := getFrom(t, client); got != "secondary" {
+		t.Errorf("request served by %s after primary failed, want secondary", got)
+	}
+	backends := client.Backends()
+	if len(backends) != 2 || backends[0].URL != secondary.URL+"/" || backends[1].Health != BackendDown || backends[1].Err == nil {
+		t.Errorf("Backends returned %+v, want secondary first and primary down", backends)
+	}
+
+	// The primary is not used again before the next health check.
+	primary.failing.Store(false)
+	if got := getFrom(t, client); got != "secondary" {
+		t.Errorf("request served by %s before the next check, want secondary", got)
+	}
+}
+
+func TestFailoverRecovery(t *testing.T) {
+	primary := newFakeBackend(t, "primary")
+	secondary := newFakeBackend(t, "secondary")
+	client := newFailoverClient(t, FailoverOptions{CheckInterval: 20 * time.Millisecond}, primary, secondary)
+
+	primary.failing.Store(true)
+	if got := getFrom(t, client); got != "secondary" {
+		t.Errorf("request served by %s after primary failed, want secondary", got)
+	}
+
+	primary.failing.Store(false)
+	time.Sleep(30 * time.Millisecond)
+	if got := getFrom(t, client); got != "primary" {
+		t.Errorf("request served by %s after primary recovered, want primary", got)
+	}
+	if got, want := backendHealth(client), []BackendHealth{BackendHealthy, BackendHealthy}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("backends have health %v, want %v", got, want)
+	}
+}
+
+func TestFailoverOrder(t *testing.T) {
+	first := newFakeBackend(t, "first")
+	second := newFakeBackend(t, "second")
+	third := newFakeBackend(t, "third")
+	first.fetching.Store(false)
+	client := newFailoverClient(t, FailoverOptions{CheckInterval: time.Hour}, first, second, third)
+
+	// Healthy backends are preferred over degraded ones, ties are broken by
+	// the order of the base URLs.
+	if got := getFrom(t, client); got != "second" {
+		t.Errorf("request served by %s, want second", got)
+	}
+	var urls []string
+	for _, b := range client.Backends() {
+		urls = append(urls, b.URL)
+	}
+	want := []string{second.URL + "/", third.URL + "/", first.URL + "/"}
+	if fmt.Sprint(urls) != fmt.Sprint(want) {
+		t.Errorf("Backends returned %v, want %v", urls, want)
+	}
+	if got, want := backendHealth(client), []BackendHealth{BackendHealthy, BackendHealthy, BackendDegraded}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("backends have health %v, want %v", got, want)
+	}
+}
+
+// failingLimiter is a RateLimiter failing every request.
+type failingLimiter struct{}
+
+func (failingLimiter) Wait(ctx context.Context) error {
+	return errors.New("rate limit exceeded")
+}
+
+func TestFailoverRateLimiterError(t *testing.T) {
+	primary := newFakeBackend(t, "primary")
+	secondary := newFakeBackend(t, "secondary")
+	client := newFailoverClient(t, FailoverOptions{CheckInterval: time.Hour}, primary, secondary)
+	client.RateLimiter = failingLimiter{}
+
+	if _, _, err := client.Resources.Get(context.Background(), 1); err == nil {
+		t.Fatal("Resources.Get returned no error")
+	}
+	for _, b := range client.Backends() {
+		if b.Health == BackendDown {
+			t.Errorf("backend %s was marked down by a rate limiter error", b.URL)
+		}
+	}
+}
+
+// TestFailoverCheckDoesNotBlock checks that a hanging health check neither
+// blocks concurrent requests nor the request triggering it for longer than
+// the CheckTimeout.
+func TestFailoverCheckDoesNotBlock(t *testing.T) {
+	primary := newFakeBackend(t, "primary")
+	primary.hang = make(chan struct{})
+	defer close(primary.hang)
+	secondary := newFakeBackend(t, "secondary")
+	client := newFailoverClient(t, FailoverOptions{CheckInterval: time.Hour, CheckTimeout: 200 * time.Millisecond}, primary, secondary)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var first string
+	go func() {
+		defer wg.Done()
+		first = getFrom(t, client)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	start := time.Now()
+	if got := getFrom(t, client); got != "primary" {
+		t.Errorf("concurrent request served by %s, want primary", got)
+	}
+	if d := time.Since(start); d > 150*time.Millisecond {
+		t.Errorf("concurrent request took %v while the check was running", d)
+	}
+
+	wg.Wait()
+	if first != "secondary" {
+		t.Errorf("request triggering the check served by %s, want secondary", first)
+	}
+	if got, want := backendHealth(client), []BackendHealth{BackendHealthy, BackendDown}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("backends have health %v, want %v", got, want)
+	}
+}
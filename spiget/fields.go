@@ -0,0 +1,72 @@
+package spiget
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Field is the JSON name of a model field. Spiget only returns the requested
+// fields when ListOptions.Fields is set, so fields should be selected using
+// the generated constants such as ResourceFieldName or AuthorFieldIcon.
+type Field string
+
+// Fields returns the names of fields for use as ListOptions.Fields, e.g.
+// Fields(ResourceFieldID, ResourceFieldName).
+func Fields(fields ...Field) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = string(f)
+	}
+	return names
+}
+
+// fieldSet is the ordered list of known fields of a model.
+type fieldSet []Field
+
+// index returns the position of f in s, or -1 if f is not a known field.
+func (s fieldSet) index(f Field) int {
+	for i, known := range s {
+		if known == f {
+			return i
+		}
+	}
+	return -1
+}
+
+// validate returns an error for the first element of fields which is not a
+// known field of model.
+func (s fieldSet) validate(model string, fields []string) error {
+	for _, f := range fields {
+		if s.index(Field(f)) < 0 {
+			return fmt.Errorf("%w: unknown %s field %q", ErrInvalidRequest, model, f)
+		}
+	}
+	return nil
+}
+
+// fieldMask records which fields of a model were present in the JSON object
+// it was decoded from. Bit i is set if the i-th field of the model's fieldSet
+// was present.
+type fieldMask uint64
+
+// record sets the bits of all fields in s present in the JSON object data.
+func (m *fieldMask) record(s fieldSet, data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*m = 0
+	for name := range raw {
+		if i := s.index(Field(name)); i >= 0 {
+			*m |= 1 << uint(i)
+		}
+	}
+	return nil
+}
+
+// has reports whether the bit of f in s is set.
+func (m fieldMask) has(s fieldSet, f Field) bool {
+	i := s.index(f)
+	return i >= 0 && m&(1<<uint(i)) != 0
+}
@@ -0,0 +1,28 @@
+package spiget
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	got := Fields(ResourceFieldID, ResourceFieldName)
+	if want := []string{"id", "name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields returned %q, want %q", got, want)
+	}
+
+	u, err := addOptions("resources", &ResourceListOptions{ListOptions: ListOptions{Fields: got}})
+	if err != nil {
+		t.Fatalf("addOptions returned error: %v", err)
+	}
+	if want := "resources?fields=id%2Cname"; u != want {
+		t.Errorf("addOptions returned %q, want %q", u, want)
+	}
+
+	// Plain strings are accepted as well, and validated the same way.
+	_, err = addOptions("resources", &ResourceListOptions{ListOptions: ListOptions{Fields: []string{"id", "bogus"}}})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("addOptions returned error %v for an unknown field, want %v", err, ErrInvalidRequest)
+	}
+}
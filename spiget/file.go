@@ -0,0 +1,133 @@
+package spiget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrSizeMismatch is matched by the *SizeMismatchError returned when a
+// downloaded file does not have the advertised size.
+var ErrSizeMismatch = errors.New("file size mismatch")
+
+// SizeMismatchError reports a download whose size differs from File.Size by
+// more than the tolerance. This indicates that the file changed since Spiget
+// fetched the resource, or was tampered with.
+type SizeMismatchError struct {
+	Expected  int64 // advertised size in bytes
+	Tolerance int64 // allowed difference in bytes
+	Actual    int64 // received bytes
+}
+
+func (e *SizeMismatchError) Error() string {
+	return fmt.Sprintf("file size mismatch: received %d bytes, expected %d ± %d bytes", e.Actual, e.Expected, e.Tolerance)
+}
+
+// Is reports whether target is ErrSizeMismatch.
+func (e *SizeMismatchError) Is(target error) bool {
+	return target == ErrSizeMismatch
+}
+
+// sizeUnits maps the size units Spiget emits, and their IEC spellings, to
+// bytes. SpigotMC uses binary prefixes, i.e. 1 KB is 1024 bytes.
+var sizeUnits = map[string]int64{
+	"":      1,
+	"B":     1,
+	"BYTE":  1,
+	"BYTES": 1,
+	"KB":    1 << 10,
+	"KIB":   1 << 10,
+	"MB":    1 << 20,
+	"MIB":   1 << 20,
+	"GB":    1 << 30,
+	"GIB":   1 << 30,
+	"TB":    1 << 40,
+	"TIB":   1 << 40,
+}
+
+// unitBytes returns the number of bytes per SizeUnit.
+func (f *File) unitBytes() (int64, error) {
+	unit, ok := sizeUnits[strings.ToUpper(strings.TrimSpace(f.SizeUnit))]
+	if !ok {
+		return 0, fmt.Errorf("unknown file size unit %q", f.SizeUnit)
+	}
+	return unit, nil
+}
+
+// Bytes returns the size of the file in bytes. Since Size is rounded by
+// SpigotMC, e.g. to "18.5 MB", the result is only exact to SizeTolerance.
+func (f *File) Bytes() (int64, error) {
+	unit, err := f.unitBytes()
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Round(f.Size * float64(unit))), nil
+}
+
+// SizeTolerance returns the maximum difference between Bytes and the actual
+// size of the file, which is one step of the last digit of Size.
+func (f *File) SizeTolerance() (int64, error) {
+	unit, err := f.unitBytes()
+	if err != nil {
+		return 0, err
+	}
+	s := strconv.FormatFloat(f.Size, 'f', -1, 64)
+	decimals := 0
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		decimals = len(s) - i - 1
+	}
+	return int64(math.Ceil(float64(unit) / math.Pow10(decimals))), nil
+}
+
+// CheckSize returns a *SizeMismatchError if n bytes differ from the size of
+// the file by more than SizeTolerance.
+func (f *File) CheckSize(n int64) error {
+	expected, err := f.Bytes()
+	if err != nil {
+		return err
+	}
+	tolerance, err := f.SizeTolerance()
+	if err != nil {
+		return err
+	}
+	if diff := n - expected; diff > tolerance || -diff > tolerance {
+		return &SizeMismatchError{Expected: expected, Tolerance: tolerance, Actual: n}
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// DownloadVerifiedTo downloads the latest version of the resource, writes
+// the file to w and checks its size against resource.File. Files of a
+// different size fail with a *SizeMismatchError after being written to w,
+// so w should be discarded on error.
+//
+// Premium resources fail with ErrPremiumResource without sending a request.
+// The size of external resources is not checked, as it is unknown.
+func (r *ResourcesService) DownloadVerifiedTo(ctx context.Context, resource *Resource, w io.Writer) (*Response, error) {
+	if resource.Premium {
+		return nil, &PremiumResourceError{ResourceID: resource.ID}
+	}
+
+	cw := &countingWriter{w: w}
+	resp, err := r.download(ctx, resource.ID, resource, "resources/"+strconv.Itoa(resource.ID)+"/download", cw)
+	if err != nil || resource.External || resource.File.Size <= 0 {
+		return resp, err
+	}
+	return resp, resource.File.CheckSize(cw.n)
+}
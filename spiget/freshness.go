@@ -0,0 +1,198 @@
+package spiget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// spigotPageSize is the number of resources on a page of the SpigotMC
+// resource list, which the fetch of Spiget walks through.
+const spigotPageSize = 20
+
+// Freshness describes how up to date the data of Spiget is, as interpreted
+// from its status.
+type Freshness struct {
+	// LastFetch is the end of the last completed fetch of SpigotMC. It is
+	// zero if no fetch completed yet.
+	LastFetch time.Time
+
+	// Age is the time since LastFetch, at the time of the report.
+	Age time.Duration
+
+	// FetchActive reports whether a fetch is running, which started at
+	// FetchStart. FetchProgress estimates its progress in percent from the
+	// page and item it is at.
+	FetchActive   bool
+	FetchStart    time.Time
+	FetchProgress float64
+
+	// RestFetchActive reports whether the fetch using the SpigotMC REST API
+	// is running. LastRestFetch is the end of the last one.
+	RestFetchActive bool
+	LastRestFetch   time.Time
+
+	// ExistenceActive reports whether the check for deleted resources is
+	// running. ExistenceProgress is its progress in percent.
+	ExistenceActive   bool
+	ExistenceProgress float64
+}
+
+func (f *Freshness) String() string {
+	return Stringify(f)
+}
+
+// Stale reports whether the last fetch ended longer than maxAge ago. A
+// report without a completed fetch is always stale.
+func (f *Freshness) Stale(maxAge time.Duration) bool {
+	return f.LastFetch.IsZero() || f.Age > maxAge
+}
+
+// newFreshness interprets s at now.
+func newFreshness(s *Status, now time.Time) *Freshness {
+	f := &Freshness{
+		LastFetch:       fetchTime(s.Fetch.End),
+		FetchActive:     s.Fetch.Active,
+		FetchStart:      fetchTime(s.Fetch.Start),
+		RestFetchActive: s.RestFetch.Active,
+		LastRestFetch:   fetchTime(s.RestFetch.End),
+		ExistenceActive: s.Existence.Active,
+	}
+	if !f.LastFetch.IsZero() {
+		f.Age = now.Sub(f.LastFetch)
+	}
+	if page := s.Fetch.Page; f.FetchActive && page.Amount > 0 {
+		done := float64(page.Index) + float64(page.Item.Index)/spigotPageSize
+		f.FetchProgress = percent(done, float64(page.Amount))
+	}
+	if doc := s.Existence.Document; f.ExistenceActive && doc.Amount > 0 {
+		f.ExistenceProgress = percent(float64(doc.Index), float64(doc.Amount))
+	}
+	return f
+}
+
+// percent returns n/total in percent, clamped to [0, 100].
+func percent(n, total float64) float64 {
+	p := n / total * 100
+	if p < 0 {
+		return 0
+	}
+	if p > 100 {
+		return 100
+	}
+	return p
+}
+
+// Freshness gets the status of the API and reports how up to date its data
+// is.
+func (s *StatusService) Freshness(ctx context.Context) (*Freshness, *Response, error) {
+	sr, resp, err := s.Get(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+	if sr == nil || sr.Status == nil {
+		return nil, resp, errors.New("status response has no status")
+	}
+	return newFreshness(sr.Status, time.Now()), resp, nil
+}
+
+// DataAgeWarning is attached to responses by clients created with
+// WithDataAgeWarning when the last fetch of SpigotMC by Spiget is stale.
+type DataAgeWarning struct {
+	LastFetch   time.Time
+	Age         time.Duration
+	FetchActive bool
+}
+
+func (w *DataAgeWarning) String() string {
+	if w.LastFetch.IsZero() {
+		return "spiget has not completed a fetch of SpigotMC yet"
+	}
+	return fmt.Sprintf("spiget data is %v old, last fetch ended %v", w.Age.Round(time.Minute), w.LastFetch.UTC().Format(time.RFC3339))
+}
+
+// WithDataAgeWarning sets Response.DataAge on responses while the last fetch
+// of SpigotMC by Spiget ended longer than maxAge ago. The status of the API
+// is requested at most every checkInterval, which defaults to five minutes.
+func WithDataAgeWarning(maxAge, checkInterval time.Duration) Option {
+	return func(c *Client) error {
+		if maxAge <= 0 {
+			return fmt.Errorf("invalid maximum data age %v: must be positive", maxAge)
+		}
+		if checkInterval < 0 {
+			return fmt.Errorf("invalid check interval %v: must not be negative", checkInterval)
+		}
+		if checkInterval == 0 {
+			checkInterval = 5 * time.Minute
+		}
+		c.dataAge = &dataAgeMonitor{maxAge: maxAge, checkInterval: checkInterval}
+		return nil
+	}
+}
+
+// dataAgeMonitor caches the freshness of the API for WithDataAgeWarning. It
+// is shared by the copies made with Client.With.
+type dataAgeMonitor struct {
+	maxAge        time.Duration
+	checkInterval time.Duration
+
+	mu        sync.Mutex
+	freshness *Freshness
+	checked   time.Time
+}
+
+// warning returns the warning for the current freshness of the API, or nil.
+// The status is requested by at most one request per checkInterval, whether
+// it succeeds or not. It is requested without holding m.mu, so concurrent
+// requests use the last known freshness instead of waiting.
+func (m *dataAgeMonitor) warning(ctx context.Context, c *Client) *DataAgeWarning {
+	m.mu.Lock()
+	check := time.Since(m.checked) >= m.checkInterval
+	if check {
+		m.checked = time.Now()
+	}
+	m.mu.Unlock()
+
+	if check {
+		// The status is requested without the monitor to not recurse. If it
+		// fails, the last known freshness is used.
+		if probe, err := c.With(); err == nil {
+			probe.dataAge = nil
+			if f, _, err := probe.Status.Freshness(ctx); err == nil {
+				m.mu.Lock()
+				m.freshness = f
+				m.mu.Unlock()
+			}
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.freshness == nil {
+		return nil
+	}
+
+	f := *m.freshness
+	if !f.LastFetch.IsZero() {
+		f.Age = time.Since(f.LastFetch)
+	}
+	if !f.Stale(m.maxAge) {
+		return nil
+	}
+	return &DataAgeWarning{LastFetch: f.LastFetch, Age: f.Age, FetchActive: f.FetchActive}
+}
+
+// middleware returns the Middleware attaching warnings to the responses of c.
+func (m *dataAgeMonitor) middleware(c *Client, next Doer) Doer {
+	return DoerFunc(func(ctx context.Context, req *http.Request) (*Response, error) {
+		resp, err := next.Do(ctx, req)
+		if err != nil || resp == nil || EndpointOf(req).Operation == "Status.Get" {
+			return resp, err
+		}
+		resp.DataAge = m.warning(ctx, c)
+		return resp, nil
+	})
+}
@@ -0,0 +1,165 @@
+package spiget
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewFreshness(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := &Status{}
+	s.Fetch.End = now.Add(-3 * time.Hour).UnixMilli()
+	s.Fetch.Active = true
+	s.Fetch.Page.Amount = 10
+	s.Fetch.Page.Index = 4
+	s.Fetch.Page.Item.Index = 10
+
+	f := newFreshness(s, now)
+	if f.Age != 3*time.Hour {
+		t.Errorf("Age is %v, want 3h", f.Age)
+	}
+	if f.FetchProgress != 45 {
+		t.Errorf("FetchProgress is %v, want 45", f.FetchProgress)
+	}
+	if !f.Stale(time.Hour) || f.Stale(4*time.Hour) {
+		t.Errorf("Stale reports a wrong result for age %v", f.Age)
+	}
+	if !newFreshness(&Status{}, now).Stale(time.Hour) {
+		t.Error("Stale reports false without a completed fetch")
+	}
+}
+
+// statusServer is a fake API counting the status requests it serves.
+type statusServer struct {
+	*httptest.Server
+
+	statusCalls int32
+	fail        bool
+	hang        chan struct{}
+	fetchEnd    time.Time
+}
+
+func newStatusServer(t *testing.T, fetchEnd time.Time) *statusServer {
+	s := &statusServer{fetchEnd: fetchEnd}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/status" {
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		atomic.AddInt32(&s.statusCalls, 1)
+		if s.hang != nil {
+			<-s.hang
+		}
+		if s.fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"status": {"fetch": {"end": %d}}}`, s.fetchEnd.UnixMilli())
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *statusServer) calls() int {
+	return int(atomic.LoadInt32(&s.statusCalls))
+}
+
+func TestDataAgeWarning(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		age   time.Duration
+		stale bool
+	}{
+		{"fresh", time.Hour, false},
+		{"stale", 48 * time.Hour, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStatusServer(t, time.Now().Add(-tt.age))
+			client, err := New(WithBaseURL(server.URL+"/"), WithDataAgeWarning(24*time.Hour, time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 3; i++ {
+				_, resp, err := client.Resources.Get(context.Background(), 1)
+				if err != nil {
+					t.Fatalf("Resources.Get returned error: %v", err)
+				}
+				if (resp.DataAge != nil) != tt.stale {
+					t.Errorf("response has DataAge %v, want stale %v", resp.DataAge, tt.stale)
+				}
+				if resp.DataAge != nil && resp.DataAge.Age < tt.age {
+					t.Errorf("DataAge.Age is %v, want at least %v", resp.DataAge.Age, tt.age)
+				}
+			}
+			if got := server.calls(); got != 1 {
+				t.Errorf("status requested %d times, want 1", got)
+			}
+		})
+	}
+}
+
+func TestDataAgeWarningStatusFailure(t *testing.T) {
+	server := newStatusServer(t, time.Time{})
+	server.fail = true
+	client, err := New(WithBaseURL(server.URL+"/"), WithDataAgeWarning(time.Hour, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, resp, err := client.Resources.Get(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("Resources.Get returned error: %v", err)
+		}
+		if resp.DataAge != nil {
+			t.Errorf("response has DataAge %v without a known status", resp.DataAge)
+		}
+	}
+	if got := server.calls(); got != 1 {
+		t.Errorf("failing status requested %d times, want 1", got)
+	}
+}
+
+// TestDataAgeWarningDoesNotBlock checks that requests do not wait for the
+// status requested by another request.
+func TestDataAgeWarningDoesNotBlock(t *testing.T) {
+	server := newStatusServer(t, time.Now())
+	server.hang = make(chan struct{})
+	client, err := New(WithBaseURL(server.URL+"/"), WithDataAgeWarning(time.Hour, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, _, err := client.Resources.Get(context.Background(), 1); err != nil {
+			t.Errorf("Resources.Get returned error: %v", err)
+		}
+	}()
+	for server.calls() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.Resources.Get(context.Background(), 1)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("request waited for the status requested by another request")
+	}
+
+	close(server.hang)
+	wg.Wait()
+}
@@ -0,0 +1,202 @@
+//go:build ignore
+// +build ignore
+
+// gen-fields generates the Field constants, field sets and presence tracking
+// methods for the models that support field projection via
+// ListOptions.Fields. The field names are taken from the json struct tags of
+// each model.
+//
+// It is meant to be used by go-spiget contributors in conjunction with the
+// go generate tool whenever one of these models changes.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const fileName = "spiget-fields.go"
+
+var (
+	verbose = flag.Bool("v", false, "Print verbose log messages")
+
+	// models lists the struct types for which field selectors are generated.
+	models = map[string]bool{
+		"Author":   true,
+		"Category": true,
+		"Resource": true,
+	}
+
+	sourceTmpl = template.Must(template.New("source").Parse(source))
+)
+
+func logf(fmt string, args ...interface{}) {
+	if *verbose {
+		log.Printf(fmt, args...)
+	}
+}
+
+func main() {
+	flag.Parse()
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, ".", sourceFilter, 0)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	for pkgName, pkg := range pkgs {
+		t := &templateData{
+			filename: pkgName + "-fields.go",
+			Package:  pkgName,
+		}
+		for filename, f := range pkg.Files {
+			logf("Processing %v...", filename)
+			if err := t.processAST(f); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if err := t.dump(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	logf("Done.")
+}
+
+func sourceFilter(fi os.FileInfo) bool {
+	return !strings.HasSuffix(fi.Name(), "_test.go") && !strings.HasSuffix(fi.Name(), fileName)
+}
+
+type templateData struct {
+	filename string
+	Package  string
+	Models   []*model
+}
+
+type model struct {
+	Name   string
+	Var    string
+	Fields []*field
+}
+
+type field struct {
+	Const string
+	Name  string
+}
+
+func (t *templateData) processAST(f *ast.File) error {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || !models[ts.Name.Name] {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			m := &model{
+				Name: ts.Name.Name,
+				Var:  strings.ToLower(ts.Name.Name[:1]) + ts.Name.Name[1:] + "Fields",
+			}
+			for _, fld := range st.Fields.List {
+				if len(fld.Names) == 0 || fld.Tag == nil {
+					continue
+				}
+				fieldName := fld.Names[0].Name
+				if !ast.IsExported(fieldName) {
+					continue
+				}
+				tag := reflect.StructTag(strings.Trim(fld.Tag.Value, "`")).Get("json")
+				name := strings.Split(tag, ",")[0]
+				if name == "" || name == "-" {
+					logf("Skipping %v.%v without json name", m.Name, fieldName)
+					continue
+				}
+				m.Fields = append(m.Fields, &field{
+					Const: m.Name + "Field" + fieldName,
+					Name:  name,
+				})
+			}
+			t.Models = append(t.Models, m)
+		}
+	}
+	return nil
+}
+
+func (t *templateData) dump() error {
+	if len(t.Models) == 0 {
+		logf("No models for %v; skipping.", t.filename)
+		return nil
+	}
+
+	sort.Slice(t.Models, func(i, j int) bool { return t.Models[i].Name < t.Models[j].Name })
+
+	var buf bytes.Buffer
+	if err := sourceTmpl.Execute(&buf, t); err != nil {
+		return err
+	}
+	clean, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format.Source:\n%v\n%v", buf.String(), err)
+	}
+
+	logf("Writing %v...", t.filename)
+	return os.WriteFile(t.filename, clean, 0644)
+}
+
+const source = `// Code generated by gen-fields; DO NOT EDIT.
+// Instead, please run "go generate ./..." in the spiget directory.
+
+package {{.Package}}
+
+import "encoding/json"
+{{range .Models}}
+// Fields of {{.Name}} that can be selected via ListOptions.Fields.
+const (
+{{- range .Fields}}
+	{{.Const}} Field = "{{.Name}}"
+{{- end}}
+)
+
+// {{.Var}} lists all known fields of {{.Name}}.
+var {{.Var}} = fieldSet{
+{{- range .Fields}}
+	{{.Const}},
+{{- end}}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface and records which
+// fields were present in the JSON object.
+func ({{.Var | printf "%.1s"}} *{{.Name}}) UnmarshalJSON(data []byte) error {
+	type alias{{.Name}} {{.Name}} // avoid infinite recursion by using type alias.
+	if err := json.Unmarshal(data, (*alias{{.Name}})({{.Var | printf "%.1s"}})); err != nil {
+		return err
+	}
+	return {{.Var | printf "%.1s"}}.present.record({{.Var}}, data)
+}
+
+// HasField reports whether f was present in the JSON object {{.Name}} was
+// decoded from. Fields trimmed by ListOptions.Fields are reported as absent.
+func ({{.Var | printf "%.1s"}} *{{.Name}}) HasField(f Field) bool {
+	return {{.Var | printf "%.1s"}}.present.has({{.Var}}, f)
+}
+{{end}}
+`
@@ -0,0 +1,154 @@
+package spiget
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // register GIF decoding for Icon.Image
+	_ "image/jpeg" // register JPEG decoding for Icon.Image
+	_ "image/png"  // register PNG decoding for Icon.Image
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// spigotBaseURL is the base of the relative URLs of icons.
+const spigotBaseURL = "https://www.spigotmc.org/"
+
+// maxIconSize limits the size of icons fetched by GetIcon.
+const maxIconSize = 10 << 20
+
+// ErrNoIcon is returned for icons without data or URL, e.g. of resources
+// using the default icon.
+var ErrNoIcon = errors.New("no icon")
+
+// Bytes returns the decoded image data of the icon as included in API
+// responses.
+func (i *Icon) Bytes() ([]byte, error) {
+	data := i.Data
+	if strings.HasPrefix(data, "data:") {
+		if _, payload, ok := strings.Cut(data, ","); ok {
+			data = payload
+		}
+	}
+	if data == "" {
+		return nil, ErrNoIcon
+	}
+	return base64.StdEncoding.DecodeString(data)
+}
+
+// Image decodes the image data of the icon as included in API responses.
+// PNG, JPEG and GIF images are supported.
+func (i *Icon) Image() (image.Image, error) {
+	data, err := i.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// AbsoluteURL returns the URL of the full-size icon, resolving relative URLs
+// against spigotmc.org.
+func (i *Icon) AbsoluteURL() (string, error) {
+	if i.URL == "" {
+		return "", ErrNoIcon
+	}
+	base, _ := url.Parse(spigotBaseURL)
+	u, err := base.Parse(i.URL)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// GetIcon fetches the full-size image of the icon from its AbsoluteURL. The
+// request is sent through the transport and middleware of the client, but
+// only carries its User-Agent: headers added with WithHeader are meant for
+// the Spiget API and are not sent to spigotmc.org. Icons larger than 10 MiB
+// are rejected.
+func (c *Client) GetIcon(ctx context.Context, icon *Icon) ([]byte, *Response, error) {
+	u, err := icon.AbsoluteURL()
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "image/*")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.BareDo(ctx, req)
+	if err != nil {
+		return nil, resp, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxIconSize+1))
+	if err != nil {
+		return nil, resp, err
+	}
+	if len(data) > maxIconSize {
+		return nil, resp, fmt.Errorf("icon %s exceeds %d bytes", u, maxIconSize)
+	}
+	return data, resp, nil
+}
+
+// Thumbnail scales img down to fit into a square of size pixels, keeping its
+// aspect ratio. Images already fitting are returned unchanged.
+func Thumbnail(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if size <= 0 || w <= size && h <= size {
+		return img
+	}
+
+	tw, th := size, size
+	if w > h {
+		th = h * size / w
+	} else {
+		tw = w * size / h
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+
+	// Each pixel of the thumbnail is the average of the area of the source
+	// it covers.
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+(x+1)*w/tw
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(bl / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
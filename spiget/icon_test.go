@@ -0,0 +1,47 @@
+package spiget
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetIcon(t *testing.T) {
+	icon := []byte("\x89PNG icon")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Api-Key"); got != "" {
+			t.Errorf("icon request carried X-Api-Key %q", got)
+		}
+		if got := r.Header.Get("User-Agent"); got != "test-agent" {
+			t.Errorf("icon request carried User-Agent %q, want %q", got, "test-agent")
+		}
+		if r.URL.Path == "/large.png" {
+			w.Write(bytes.Repeat([]byte{0}, maxIconSize+1))
+			return
+		}
+		w.Write(icon)
+	}))
+	defer server.Close()
+
+	client, err := New(WithHeader("X-Api-Key", "secret"), WithUserAgent("test-agent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _, err := client.GetIcon(context.Background(), &Icon{URL: server.URL + "/icon.png"})
+	if err != nil {
+		t.Fatalf("GetIcon returned error: %v", err)
+	}
+	if !bytes.Equal(data, icon) {
+		t.Errorf("GetIcon returned %q, want %q", data, icon)
+	}
+
+	if _, _, err := client.GetIcon(context.Background(), &Icon{URL: server.URL + "/large.png"}); err == nil {
+		t.Error("GetIcon returned no error for an icon exceeding the size limit")
+	}
+	if _, _, err := client.GetIcon(context.Background(), &Icon{}); err != ErrNoIcon {
+		t.Errorf("GetIcon returned error %v for an empty icon, want %v", err, ErrNoIcon)
+	}
+}
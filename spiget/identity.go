@@ -0,0 +1,123 @@
+package spiget
+
+import (
+	"context"
+	"image"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// IdentityProvider is a service an author linked their SpigotMC account
+// with. It is the key of Author.Identities.
+type IdentityProvider string
+
+// Identity providers with known profile URLs.
+const (
+	IdentityDiscord IdentityProvider = "discord"
+	IdentityGitHub  IdentityProvider = "github"
+	IdentityTwitter IdentityProvider = "twitter"
+	IdentityYouTube IdentityProvider = "youtube"
+)
+
+// Identity is an account of an author on another service.
+type Identity struct {
+	Provider IdentityProvider
+	Name     string
+
+	// URL is the profile of the account. It is empty if the account has no
+	// public profile, e.g. for Discord usernames.
+	URL string
+}
+
+var (
+	discordUserIDRE    = regexp.MustCompile(`^[0-9]{17,20}$`)
+	youTubeChannelIDRE = regexp.MustCompile(`^UC[0-9A-Za-z_-]{22}$`)
+)
+
+// Identity returns the identity of the author on the provider. Providers
+// are matched case-insensitively.
+func (a *Author) Identity(provider IdentityProvider) (Identity, bool) {
+	for key, value := range a.Identities {
+		if strings.EqualFold(key, string(provider)) && strings.TrimSpace(value) != "" {
+			return newIdentity(provider, value), true
+		}
+	}
+	return Identity{}, false
+}
+
+// Discord returns the Discord identity of the author. Its URL is only set
+// for numeric user IDs.
+func (a *Author) Discord() (Identity, bool) { return a.Identity(IdentityDiscord) }
+
+// GitHub returns the GitHub identity of the author.
+func (a *Author) GitHub() (Identity, bool) { return a.Identity(IdentityGitHub) }
+
+// Twitter returns the Twitter identity of the author.
+func (a *Author) Twitter() (Identity, bool) { return a.Identity(IdentityTwitter) }
+
+// YouTube returns the YouTube identity of the author.
+func (a *Author) YouTube() (Identity, bool) { return a.Identity(IdentityYouTube) }
+
+// IdentityList returns all identities of the author, sorted by provider.
+// Providers without known profile URLs are included with an empty URL.
+func (a *Author) IdentityList() []Identity {
+	var identities []Identity
+	for key, value := range a.Identities {
+		if strings.TrimSpace(value) != "" {
+			identities = append(identities, newIdentity(IdentityProvider(strings.ToLower(key)), value))
+		}
+	}
+	sort.Slice(identities, func(i, j int) bool { return identities[i].Provider < identities[j].Provider })
+	return identities
+}
+
+// newIdentity interprets value, which is either an account name or a
+// profile URL.
+func newIdentity(provider IdentityProvider, value string) Identity {
+	value = strings.TrimSpace(value)
+	id := Identity{Provider: provider, Name: value}
+
+	if u, err := url.Parse(value); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		id.URL = u.String()
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		id.Name = segments[len(segments)-1]
+		return id
+	}
+
+	name := strings.TrimPrefix(value, "@")
+	switch provider {
+	case IdentityGitHub:
+		id.Name = name
+		id.URL = "https://github.com/" + url.PathEscape(name)
+	case IdentityTwitter:
+		id.Name = name
+		id.URL = "https://twitter.com/" + url.PathEscape(name)
+	case IdentityYouTube:
+		switch {
+		case youTubeChannelIDRE.MatchString(value):
+			id.URL = "https://www.youtube.com/channel/" + value
+		case strings.HasPrefix(value, "@"):
+			id.URL = "https://www.youtube.com/" + url.PathEscape(value)
+		default:
+			id.URL = "https://www.youtube.com/user/" + url.PathEscape(value)
+		}
+	case IdentityDiscord:
+		if discordUserIDRE.MatchString(value) {
+			id.URL = "https://discord.com/users/" + value
+		}
+	}
+	return id
+}
+
+// Avatar decodes the avatar of the author as included in API responses.
+func (a *Author) Avatar() (image.Image, error) {
+	return a.Icon.Image()
+}
+
+// GetAvatar fetches the full-size avatar of the author from spigotmc.org.
+// See Client.GetIcon.
+func (s *AuthorsService) GetAvatar(ctx context.Context, author *Author) ([]byte, *Response, error) {
+	return s.client.GetIcon(ctx, &author.Icon)
+}
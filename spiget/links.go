@@ -0,0 +1,273 @@
+package spiget
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// LinkKind classifies a link of a resource.
+type LinkKind string
+
+// Kinds of links recognized by ParseLink.
+const (
+	LinkGitHub    LinkKind = "github"
+	LinkGitLab    LinkKind = "gitlab"
+	LinkBitbucket LinkKind = "bitbucket"
+	LinkDiscord   LinkKind = "discord"
+	LinkWiki      LinkKind = "wiki"
+	LinkDonation  LinkKind = "donation"
+	LinkOther     LinkKind = "other"
+	LinkInvalid   LinkKind = "invalid"
+)
+
+// Link is a classified link of a resource.
+type Link struct {
+	// Name is the key of the link in Resource.Links, or "sourceCode" and
+	// "donation" for Resource.SourceCodeLink and Resource.DonationLink.
+	Name string
+
+	Kind LinkKind
+	URL  string
+
+	// Owner and Repo are set for links to repositories on code hosts. The
+	// owner of GitLab repositories may contain subgroups, e.g. "group/sub".
+	Owner string
+	Repo  string
+
+	// Invite is the invite code of Discord links.
+	Invite string
+}
+
+// IsRepository reports whether the link points to a repository on a code
+// host.
+func (l Link) IsRepository() bool {
+	return l.Owner != "" && l.Repo != ""
+}
+
+// donationHosts are the hosts of donation platforms.
+var donationHosts = []string{
+	"paypal.me", "paypal.com", "ko-fi.com", "patreon.com", "buymeacoffee.com",
+	"opencollective.com", "liberapay.com", "streamlabs.com",
+}
+
+// wikiHosts are the hosts of documentation sites.
+var wikiHosts = []string{"gitbook.io", "readthedocs.io", "fandom.com", "wiki.gg"}
+
+// githubReserved are top-level paths of github.com which are not owners of
+// repositories. GitHub does not allow accounts with these names.
+var githubReserved = map[string]bool{
+	"about": true, "account": true, "apps": true, "blog": true, "codespaces": true,
+	"collections": true, "contact": true, "customer-stories": true, "dashboard": true,
+	"enterprise": true, "events": true, "explore": true, "features": true, "issues": true,
+	"join": true, "login": true, "logout": true, "marketplace": true, "new": true,
+	"notifications": true, "organizations": true, "orgs": true, "pricing": true,
+	"pulls": true, "readme": true, "search": true, "security": true, "sessions": true,
+	"settings": true, "signup": true, "site": true, "solutions": true, "sponsors": true,
+	"stars": true, "team": true, "topics": true, "trending": true, "users": true,
+}
+
+// gitlabReserved are top-level paths of gitlab.com which are not groups or
+// users, see https://docs.gitlab.com/ee/user/reserved_names.html.
+var gitlabReserved = map[string]bool{
+	"-": true, ".well-known": true, "admin": true, "api": true, "assets": true,
+	"dashboard": true, "explore": true, "files": true, "groups": true,
+	"health_check": true, "help": true, "import": true, "jwt": true, "login": true,
+	"oauth": true, "profile": true, "projects": true, "public": true, "s": true,
+	"search": true, "sitemap": true, "snippets": true, "unsubscribes": true,
+	"uploads": true, "users": true, "v2": true,
+}
+
+// bitbucketReserved are top-level paths of bitbucket.org which are not
+// workspaces.
+var bitbucketReserved = map[string]bool{
+	"account": true, "blog": true, "dashboard": true, "product": true, "repo": true,
+	"site": true, "snippets": true, "socialauth": true, "support": true, "workspace": true,
+}
+
+// ParseLink classifies raw. Links without a scheme are assumed to use https.
+func ParseLink(raw string) Link {
+	raw = strings.TrimSpace(raw)
+	link := Link{Kind: LinkInvalid, URL: raw}
+	if raw == "" {
+		return link
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return link
+	}
+	link.URL = u.String()
+	link.Kind = LinkOther
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	var segments []string
+	for _, s := range strings.Split(u.Path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+
+	switch host {
+	case "github.com":
+		link.Kind = LinkGitHub
+		if len(segments) >= 1 {
+			first := strings.ToLower(segments[0])
+			if first == "sponsors" {
+				link.Kind = LinkDonation
+			}
+			if githubReserved[first] {
+				return link
+			}
+		}
+		link.Owner, link.Repo = ownerRepo(segments, 2)
+		if len(segments) >= 3 && segments[2] == "wiki" {
+			link.Kind = LinkWiki
+		}
+		return link
+	case "gitlab.com":
+		link.Kind = LinkGitLab
+		if len(segments) >= 1 && gitlabReserved[strings.ToLower(segments[0])] {
+			return link
+		}
+		// Paths of subpages of repositories are separated by "-", e.g.
+		// group/sub/repo/-/wikis.
+		repoPath := segments
+		for i, s := range segments {
+			if s == "-" {
+				repoPath = segments[:i]
+				if i+1 < len(segments) && segments[i+1] == "wikis" {
+					link.Kind = LinkWiki
+				}
+				break
+			}
+		}
+		link.Owner, link.Repo = ownerRepo(repoPath, len(repoPath))
+		return link
+	case "bitbucket.org":
+		link.Kind = LinkBitbucket
+		if len(segments) >= 1 && bitbucketReserved[strings.ToLower(segments[0])] {
+			return link
+		}
+		link.Owner, link.Repo = ownerRepo(segments, 2)
+		if len(segments) >= 3 && segments[2] == "wiki" {
+			link.Kind = LinkWiki
+		}
+		return link
+	case "discord.gg":
+		link.Kind = LinkDiscord
+		if len(segments) >= 1 {
+			link.Invite = segments[0]
+		}
+		return link
+	case "discord.com", "discordapp.com":
+		link.Kind = LinkDiscord
+		if len(segments) >= 2 && segments[0] == "invite" {
+			link.Invite = segments[1]
+		}
+		return link
+	}
+
+	switch {
+	case hostIn(host, donationHosts):
+		link.Kind = LinkDonation
+	case hostIn(host, wikiHosts) || strings.HasPrefix(host, "wiki.") || strings.HasPrefix(host, "docs."):
+		link.Kind = LinkWiki
+	case len(segments) >= 1 && strings.EqualFold(segments[0], "wiki"):
+		link.Kind = LinkWiki
+	}
+	return link
+}
+
+// ownerRepo returns the owner and repository of a code host path whose
+// repository is at segment n. Both are empty unless the path has the shape
+// of a repository.
+func ownerRepo(segments []string, n int) (owner, repo string) {
+	if n < 2 || len(segments) < n {
+		return "", ""
+	}
+	for _, s := range segments[:n-1] {
+		if !isPathName(s) || s[0] == '.' || s[0] == '-' {
+			return "", ""
+		}
+	}
+	repo = strings.TrimSuffix(segments[n-1], ".git")
+	if !isPathName(repo) || repo == "." || repo == ".." {
+		return "", ""
+	}
+	return strings.Join(segments[:n-1], "/"), repo
+}
+
+// isPathName reports whether s is non-empty and only contains the characters
+// code hosts allow in the names of owners and repositories.
+func isPathName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// hostIn reports whether host is one of hosts or a subdomain of one.
+func hostIn(host string, hosts []string) bool {
+	for _, h := range hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsedLinks returns the classified Links of the resource sorted by name,
+// followed by SourceCodeLink and DonationLink. Empty links are skipped.
+func (r *Resource) ParsedLinks() []Link {
+	var links []Link
+	add := func(name, raw string) {
+		if strings.TrimSpace(raw) == "" {
+			return
+		}
+		link := ParseLink(raw)
+		link.Name = name
+		links = append(links, link)
+	}
+	for name, raw := range r.Links {
+		add(name, raw)
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].Name < links[j].Name })
+
+	add("sourceCode", r.SourceCodeLink)
+	add("donation", r.DonationLink)
+	return links
+}
+
+// SourceRepository returns the repository the source code of the resource
+// is hosted in. SourceCodeLink is preferred over the other links. Links to
+// the wiki of a repository are not considered.
+func (r *Resource) SourceRepository() (Link, bool) {
+	if link := ParseLink(r.SourceCodeLink); link.IsRepository() && link.Kind != LinkWiki {
+		link.Name = "sourceCode"
+		return link, true
+	}
+	for _, link := range r.ParsedLinks() {
+		if link.IsRepository() && link.Kind != LinkWiki {
+			return link, true
+		}
+	}
+	return Link{}, false
+}
+
+// IsOpenSource reports whether the resource links a source code repository
+// on GitHub, GitLab or Bitbucket. It does not check whether the repository
+// is public or actually contains the source of the resource.
+func (r *Resource) IsOpenSource() bool {
+	_, ok := r.SourceRepository()
+	return ok
+}
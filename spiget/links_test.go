@@ -0,0 +1,93 @@
+package spiget
+
+import "testing"
+
+func TestParseLink(t *testing.T) {
+	tests := []struct {
+		raw   string
+		kind  LinkKind
+		owner string
+		repo  string
+	}{
+		{"https://github.com/owner/repo", LinkGitHub, "owner", "repo"},
+		{"github.com/owner/repo.git", LinkGitHub, "owner", "repo"},
+		{"https://www.github.com/owner/repo/tree/main/src", LinkGitHub, "owner", "repo"},
+		{"https://github.com/owner/.github", LinkGitHub, "owner", ".github"},
+		{"https://github.com/owner/repo/wiki", LinkWiki, "owner", "repo"},
+		{"https://github.com/owner", LinkGitHub, "", ""},
+		{"https://github.com/features/actions", LinkGitHub, "", ""},
+		{"https://github.com/marketplace/actions/checkout", LinkGitHub, "", ""},
+		{"https://github.com/orgs/owner/repositories", LinkGitHub, "", ""},
+		{"https://github.com/topics/minecraft", LinkGitHub, "", ""},
+		{"https://github.com/Explore/repos", LinkGitHub, "", ""},
+		{"https://github.com/sponsors/owner", LinkDonation, "", ""},
+		{"https://github.com/-owner/repo", LinkGitHub, "", ""},
+		{"https://github.com/owner/..", LinkGitHub, "", ""},
+		{"https://github.com/owner/re%20po", LinkGitHub, "", ""},
+		{"https://gitlab.com/group/repo", LinkGitLab, "group", "repo"},
+		{"https://gitlab.com/group/sub/repo/-/tree/main", LinkGitLab, "group/sub", "repo"},
+		{"https://gitlab.com/group/repo/-/wikis/home", LinkWiki, "group", "repo"},
+		{"https://gitlab.com/explore/projects", LinkGitLab, "", ""},
+		{"https://gitlab.com/users/sign_in", LinkGitLab, "", ""},
+		{"https://gitlab.com/-/snippets/1", LinkGitLab, "", ""},
+		{"https://gitlab.com/group", LinkGitLab, "", ""},
+		{"https://bitbucket.org/owner/repo/src", LinkBitbucket, "owner", "repo"},
+		{"https://bitbucket.org/product/features", LinkBitbucket, "", ""},
+		{"https://discord.gg/abc", LinkDiscord, "", ""},
+		{"https://paypal.me/owner", LinkDonation, "", ""},
+		{"https://docs.example.com/", LinkWiki, "", ""},
+		{"https://example.com/wiki/Main", LinkWiki, "", ""},
+		{"https://example.com/", LinkOther, "", ""},
+		{"javascript:alert(1)", LinkInvalid, "", ""},
+		{"", LinkInvalid, "", ""},
+	}
+	for _, tt := range tests {
+		link := ParseLink(tt.raw)
+		if link.Kind != tt.kind || link.Owner != tt.owner || link.Repo != tt.repo {
+			t.Errorf("ParseLink(%q) returned kind %q, owner %q, repo %q, want %q, %q, %q",
+				tt.raw, link.Kind, link.Owner, link.Repo, tt.kind, tt.owner, tt.repo)
+		}
+	}
+}
+
+func TestParseLinkDiscordInvite(t *testing.T) {
+	for _, raw := range []string{"https://discord.gg/abc", "https://discord.com/invite/abc"} {
+		if got := ParseLink(raw).Invite; got != "abc" {
+			t.Errorf("ParseLink(%q).Invite is %q, want %q", raw, got, "abc")
+		}
+	}
+}
+
+func TestIsOpenSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource Resource
+		want     bool
+		repo     string
+	}{
+		{"source code link", Resource{SourceCodeLink: "https://github.com/owner/repo"}, true, "repo"},
+		{"other link", Resource{Links: map[string]string{"alternativeSupport": "https://gitlab.com/group/repo"}}, true, "repo"},
+		{"source code link preferred", Resource{
+			SourceCodeLink: "https://github.com/owner/source",
+			Links:          map[string]string{"a": "https://github.com/owner/other"},
+		}, true, "source"},
+		{"wiki link", Resource{Links: map[string]string{"wiki": "https://github.com/owner/repo/wiki"}}, false, ""},
+		{"source code wiki link", Resource{SourceCodeLink: "https://github.com/owner/repo/wiki"}, false, ""},
+		{"source code wiki link and other link", Resource{
+			SourceCodeLink: "https://gitlab.com/group/docs/-/wikis/home",
+			Links:          map[string]string{"a": "https://github.com/owner/other"},
+		}, true, "other"},
+		{"reserved GitHub path", Resource{SourceCodeLink: "https://github.com/features/actions"}, false, ""},
+		{"reserved GitLab path", Resource{SourceCodeLink: "https://gitlab.com/explore/projects"}, false, ""},
+		{"owner only", Resource{SourceCodeLink: "https://github.com/owner"}, false, ""},
+		{"no links", Resource{}, false, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.resource.IsOpenSource(); got != tt.want {
+			t.Errorf("IsOpenSource of %s returned %v, want %v", tt.name, got, tt.want)
+		}
+		if link, _ := tt.resource.SourceRepository(); link.Repo != tt.repo {
+			t.Errorf("SourceRepository of %s returned repository %q, want %q", tt.name, link.Repo, tt.repo)
+		}
+	}
+}
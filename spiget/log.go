@@ -0,0 +1,84 @@
+package spiget
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// LogLevel is the severity of a log record. The values match those of
+// slog.Level, so they can be converted directly.
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = -4
+	LevelInfo  LogLevel = 0
+	LevelWarn  LogLevel = 4
+	LevelError LogLevel = 8
+)
+
+// Logger receives the structured log records emitted by a Client. keyvals
+// holds alternating keys and values, as accepted by slog.Logger.Log. Use
+// NewSlogLogger to log to a *slog.Logger.
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, keyvals ...interface{})
+}
+
+// logRequest logs the start of req.
+func (c *Client) logRequest(ctx context.Context, req *http.Request) {
+	if c.Logger == nil {
+		return
+	}
+	c.Logger.Log(ctx, c.LogLevel, "spiget request started",
+		"method", req.Method,
+		"url", sanitizeURL(req.URL).String(),
+	)
+}
+
+// logResponse logs the outcome of req. Successful requests are logged at the
+// client's LogLevel, failed requests at LevelWarn for 4xx responses and at
+// LevelError otherwise.
+func (c *Client) logResponse(ctx context.Context, req *http.Request, resp *Response, err error, duration time.Duration) {
+	if c.Logger == nil {
+		return
+	}
+
+	level := c.LogLevel
+	keyvals := []interface{}{
+		"method", req.Method,
+		"url", sanitizeURL(req.URL).String(),
+		"duration", duration,
+	}
+	if resp != nil {
+		keyvals = append(keyvals, "status", resp.StatusCode)
+		if resp.LastPage > 0 {
+			keyvals = append(keyvals, "page", resp.NextPage-1, "page_count", resp.LastPage)
+		}
+		if resp.Header.Get("X-From-Cache") != "" {
+			keyvals = append(keyvals, "cache_hit", true)
+		}
+	}
+	if err != nil {
+		keyvals = append(keyvals, "error", err.Error())
+		level = LevelError
+		if IsInvalidRequest(err) || IsNotFound(err) || IsRateLimited(err) {
+			level = LevelWarn
+		}
+	}
+	c.Logger.Log(ctx, level, "spiget request finished", keyvals...)
+}
+
+// logRetry logs that req failed with err and is retried after delay. attempt
+// is the number of the retry, starting at 1.
+func (c *Client) logRetry(ctx context.Context, req *http.Request, attempt int, delay time.Duration, err error) {
+	if c.Logger == nil {
+		return
+	}
+	c.Logger.Log(ctx, LevelWarn, "spiget request retrying",
+		"method", req.Method,
+		"url", sanitizeURL(req.URL).String(),
+		"attempt", attempt,
+		"delay", delay,
+		"error", err.Error(),
+	)
+}
@@ -0,0 +1,88 @@
+package spiget
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// logRecord is a record logged to a testLogger.
+type logRecord struct {
+	level   LogLevel
+	msg     string
+	keyvals map[string]interface{}
+}
+
+// testLogger records the records logged to it.
+type testLogger struct {
+	mu      sync.Mutex
+	records []logRecord
+}
+
+func (l *testLogger) Log(ctx context.Context, level LogLevel, msg string, keyvals ...interface{}) {
+	r := logRecord{level: level, msg: msg, keyvals: make(map[string]interface{})}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		r.keyvals[keyvals[i].(string)] = keyvals[i+1]
+	}
+	l.mu.Lock()
+	l.records = append(l.records, r)
+	l.mu.Unlock()
+}
+
+// messages returns the records with the message.
+func (l *testLogger) messages(msg string) []logRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var records []logRecord
+	for _, r := range l.records {
+		if r.msg == msg {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+func TestLogRetries(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	logger := &testLogger{}
+	client, err := New(
+		WithBaseURL(server.URL+"/v2/"),
+		WithLogger(logger, LevelDebug),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Resources.Get(context.Background(), 1); err != nil {
+		t.Fatalf("Resources.Get returned error: %v", err)
+	}
+
+	retries := logger.messages("spiget request retrying")
+	if len(retries) != 2 {
+		t.Fatalf("logged %d retries, want 2", len(retries))
+	}
+	for i, r := range retries {
+		if r.level != LevelWarn {
+			t.Errorf("retry %d logged at level %v, want %v", i+1, r.level, LevelWarn)
+		}
+		if r.keyvals["attempt"] != i+1 {
+			t.Errorf("retry %d logged attempt %v", i+1, r.keyvals["attempt"])
+		}
+		if want := server.URL + "/v2/resources/1"; r.keyvals["url"] != want {
+			t.Errorf("retry %d logged url %v, want %v", i+1, r.keyvals["url"], want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package spiget
+
+import (
+	"context"
+	"net/http"
+)
+
+// Doer sends an API request and returns the API response. The error is the
+// one returned by BareDo, e.g. an *ErrorResponse for API errors.
+type Doer interface {
+	Do(ctx context.Context, req *http.Request) (*Response, error)
+}
+
+// DoerFunc is an adapter allowing the use of ordinary functions as Doers.
+type DoerFunc func(ctx context.Context, req *http.Request) (*Response, error)
+
+// Do calls f(ctx, req).
+func (f DoerFunc) Do(ctx context.Context, req *http.Request) (*Response, error) {
+	return f(ctx, req)
+}
+
+// Middleware wraps the Doer sending the requests of a Client. It may modify
+// the request before calling next, inspect or replace the response and
+// error, retry, or answer the request without calling next at all.
+type Middleware func(next Doer) Doer
+
+// Use adds middleware around BareDo, and thus around every request sent by
+// the services of the client. Middleware runs in the order it was added: the
+// first one added sees the request first and the response last.
+//
+// Use must not be called concurrently with requests.
+func (c *Client) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// doer returns the Doer sending requests through the data age monitor, the
+// cache, the retry policy, the failover and the middleware, in that order.
+func (c *Client) doer() Doer {
+	var d Doer = DoerFunc(c.bareDo)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		d = c.middleware[i](d)
+	}
+	if c.failover != nil {
+		d = c.failover.middleware(c, d)
+	}
+	if c.RetryPolicy != nil {
+		d = c.RetryPolicy.middleware(c, d)
+	}
+	if c.Cache != nil {
+		d = cacheMiddleware(c.Cache)(d)
+	}
+	if c.dataAge != nil {
+		d = c.dataAge.middleware(c, d)
+	}
+	return d
+}
@@ -25,8 +25,12 @@ type Rating struct {
 
 // Version represents a version.
 type Version struct {
-	ID   int    `json:"id,omitempty"`
-	UUID string `json:"uuid,omitempty"`
+	ID          int    `json:"id,omitempty"`
+	UUID        string `json:"uuid,omitempty"`
+	Name        string `json:"name,omitempty"`
+	ReleaseDate int    `json:"releaseDate,omitempty"`
+	Downloads   int    `json:"downloads,omitempty"`
+	Rating      Rating `json:"rating,omitempty"`
 }
 
 // Update represents an update.
@@ -0,0 +1,209 @@
+package spiget
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount of a currency, stored exactly in the smallest unit of
+// the currency, e.g. cents for USD.
+type Money struct {
+	// Amount is the number of minor units.
+	Amount int64
+
+	// Currency is the ISO 4217 code, e.g. "USD". It is empty for the prices
+	// of free resources.
+	Currency string
+}
+
+// zeroDecimalCurrencies are the currencies without minor units.
+var zeroDecimalCurrencies = map[string]bool{
+	"JPY": true, "KRW": true, "VND": true, "CLP": true, "ISK": true,
+}
+
+// currencyDecimals returns the number of decimal places of the currency.
+func currencyDecimals(currency string) int {
+	if zeroDecimalCurrencies[currency] {
+		return 0
+	}
+	return 2
+}
+
+// NewMoney returns amount of the currency, rounded to its minor unit.
+func NewMoney(amount float64, currency string) Money {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	scale := math.Pow10(currencyDecimals(currency))
+	return Money{Amount: int64(math.Round(amount * scale)), Currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "12.99" of the currency.
+func ParseMoney(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	decimals := currencyDecimals(currency)
+
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" || len(frac) > decimals {
+		return Money{}, fmt.Errorf("invalid amount %q of %s", amount, currency)
+	}
+	frac += strings.Repeat("0", decimals-len(frac))
+
+	units, err := strconv.ParseInt("0"+whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q of %s", amount, currency)
+	}
+	if negative {
+		units = -units
+	}
+	return Money{Amount: units, Currency: currency}, nil
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Decimal returns the amount as a decimal number, e.g. "12.99".
+func (m Money) Decimal() string {
+	decimals := currencyDecimals(m.Currency)
+	if decimals == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+	sign, units := "", m.Amount
+	if units < 0 {
+		sign, units = "-", -units
+	}
+	scale := int64(math.Pow10(decimals))
+	return fmt.Sprintf("%s%d.%0*d", sign, units/scale, decimals, units%scale)
+}
+
+// Float64 returns the amount in major units. It may be inexact.
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / math.Pow10(currencyDecimals(m.Currency))
+}
+
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+// Cmp compares m and o, returning -1, 0 or +1. Zero amounts compare equal
+// regardless of their currency; other amounts must be of the same currency.
+func (m Money) Cmp(o Money) (int, error) {
+	if m.Currency != o.Currency && !m.IsZero() && !o.IsZero() {
+		return 0, fmt.Errorf("cannot compare %s with %s", m.Currency, o.Currency)
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// FXProvider provides exchange rates.
+type FXProvider interface {
+	// Rate returns the exchange rate between the currencies, i.e. the
+	// amount of to worth one unit of from.
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+// StaticRates is an FXProvider using fixed rates, given as the amount of
+// each currency worth one unit of a common base currency, e.g.
+// StaticRates{"USD": 1, "EUR": 0.92}.
+type StaticRates map[string]float64
+
+// Rate implements FXProvider.
+func (r StaticRates) Rate(ctx context.Context, from, to string) (float64, error) {
+	f, ok := r[from]
+	if !ok || f <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", from)
+	}
+	t, ok := r[to]
+	if !ok || t <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", to)
+	}
+	return t / f, nil
+}
+
+// Convert converts m to the currency using fx, rounding to the minor unit of
+// the currency. Zero amounts are converted without asking fx.
+func (m Money) Convert(ctx context.Context, currency string, fx FXProvider) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if m.Currency == currency || m.IsZero() {
+		return Money{Amount: m.Amount, Currency: currency}, nil
+	}
+	if fx == nil {
+		return Money{}, fmt.Errorf("cannot convert %s to %s without exchange rates", m.Currency, currency)
+	}
+	rate, err := fx.Rate(ctx, m.Currency, currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(m.Float64()*rate, currency), nil
+}
+
+// PremiumPrice returns the price of a premium resource. It returns false for
+// free resources.
+func (r *Resource) PremiumPrice() (Money, bool) {
+	if !r.Premium {
+		return Money{}, false
+	}
+	return NewMoney(r.Price, r.Currency), true
+}
+
+// PriceRange selects resources by price. Free resources cost zero.
+type PriceRange struct {
+	// Min and Max are the inclusive bounds. Nil bounds are open.
+	Min, Max *Money
+
+	// FX converts prices in other currencies than the bounds. If nil, only
+	// prices in the currencies of the bounds can be compared.
+	FX FXProvider
+}
+
+// Contains reports whether the price of the resource is in the range.
+func (p PriceRange) Contains(ctx context.Context, r *Resource) (bool, error) {
+	price, _ := r.PremiumPrice()
+	for i, bound := range []*Money{p.Min, p.Max} {
+		if bound == nil {
+			continue
+		}
+		converted, err := price.Convert(ctx, bound.Currency, p.FX)
+		if err != nil {
+			return false, err
+		}
+		c, err := converted.Cmp(*bound)
+		if err != nil {
+			return false, err
+		}
+		if i == 0 && c < 0 || i == 1 && c > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// FilterByPrice returns the resources whose price is in the range, keeping
+// their order.
+func FilterByPrice(ctx context.Context, resources []*Resource, p PriceRange) ([]*Resource, error) {
+	var filtered []*Resource
+	for _, r := range resources {
+		ok, err := p.Contains(ctx, r)
+		if err != nil {
+			return nil, fmt.Errorf("resource %d: %w", r.ID, err)
+		}
+		if ok {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered, nil
+}
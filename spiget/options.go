@@ -0,0 +1,249 @@
+package spiget
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option configures a Client created by New.
+type Option func(c *Client) error
+
+// New returns a new Spiget API client configured by opts. Without options it
+// is equivalent to NewClient(nil). An error is returned if an option is
+// invalid.
+//
+// Options are applied in order. WithHTTPClient replaces the HTTP client
+// configured by earlier options such as WithTimeout, so it should be given
+// first.
+func New(opts ...Option) (*Client, error) {
+	c := NewClient(nil)
+	if err := c.apply(opts); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// apply applies opts to c.
+func (c *Client) apply(opts []Option) error {
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithHTTPClient makes the client send requests with a copy of httpClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("HTTP client must be non-nil")
+		}
+		clientCopy := *httpClient
+		c.client = &clientCopy
+		return nil
+	}
+}
+
+// WithBaseURL sets the base URL of the API, e.g. of a self-hosted instance.
+// A trailing slash is added if missing.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid base URL: %w", err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid base URL %q: must be an absolute http or https URL", baseURL)
+		}
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+		}
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		if strings.TrimSpace(userAgent) == "" {
+			return errors.New("user agent must be non-empty")
+		}
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithTimeout sets the time limit of every request, including reading the
+// response body. A timeout of zero means no timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("invalid timeout %v: must not be negative", timeout)
+		}
+		clientCopy := *c.client
+		clientCopy.Timeout = timeout
+		c.client = &clientCopy
+		return nil
+	}
+}
+
+// WithRetryPolicy makes the client retry failed requests according to p.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) error {
+		if err := p.validate(); err != nil {
+			return err
+		}
+		c.RetryPolicy = &p
+		return nil
+	}
+}
+
+// WithRateLimiter makes the client wait on l before sending a request.
+func WithRateLimiter(l RateLimiter) Option {
+	return func(c *Client) error {
+		if l == nil {
+			return errors.New("rate limiter must be non-nil")
+		}
+		c.RateLimiter = l
+		return nil
+	}
+}
+
+// WithCache makes the client cache the responses of GET requests in cache.
+func WithCache(cache Cache) Option {
+	return func(c *Client) error {
+		if cache == nil {
+			return errors.New("cache must be non-nil")
+		}
+		c.Cache = cache
+		return nil
+	}
+}
+
+// WithLogger sets the Logger receiving a record for every request.
+// Successful requests are logged at level.
+func WithLogger(l Logger, level LogLevel) Option {
+	return func(c *Client) error {
+		if l == nil {
+			return errors.New("logger must be non-nil")
+		}
+		c.Logger = l
+		c.LogLevel = level
+		return nil
+	}
+}
+
+// WithProxy sends requests through the proxy at proxyURL. It requires the
+// transport of the HTTP client to be nil or an *http.Transport, which is
+// cloned.
+func WithProxy(proxyURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q: must be absolute", proxyURL)
+		}
+
+		var transport *http.Transport
+		switch t := c.client.Transport.(type) {
+		case nil:
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			transport = t.Clone()
+		default:
+			return fmt.Errorf("cannot set proxy on transport of type %T", t)
+		}
+		transport.Proxy = http.ProxyURL(u)
+
+		clientCopy := *c.client
+		clientCopy.Transport = transport
+		c.client = &clientCopy
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with every request, e.g. for authenticating
+// with a proxied self-hosted instance. It may be given multiple times.
+func WithHeader(key, value string) Option {
+	return func(c *Client) error {
+		if key == "" || strings.ContainsAny(key, " \t\r\n:") {
+			return fmt.Errorf("invalid header name %q", key)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid value for header %q", key)
+		}
+		headers := c.headers.Clone()
+		if headers == nil {
+			headers = make(http.Header)
+		}
+		headers.Add(key, value)
+		c.headers = headers
+		return nil
+	}
+}
+
+// WithDecoding sets the mode response bodies are decoded with.
+func WithDecoding(mode DecodeMode) Option {
+	return func(c *Client) error {
+		if mode < DecodeDefault || mode > DecodeStrict {
+			return fmt.Errorf("invalid decode mode %d", mode)
+		}
+		c.Decoding = mode
+		return nil
+	}
+}
+
+// With returns a copy of the client configured by opts. The copy shares the
+// transport, and thus the connection pool, the rate limiter, the cache, the
+// logger and the backends of WithFailover with c, but changing its settings
+// does not affect c. Its services are bound to the copy.
+//
+// With is safe to call concurrently with requests made by c.
+func (c *Client) With(opts ...Option) (*Client, error) {
+	c.clientMu.Lock()
+	httpClient := *c.client
+	c.clientMu.Unlock()
+
+	baseURL := *c.BaseURL
+	clone := &Client{
+		client:      &httpClient,
+		BaseURL:     &baseURL,
+		UserAgent:   c.UserAgent,
+		Decoding:    c.Decoding,
+		RateLimiter: c.RateLimiter,
+		Logger:      c.Logger,
+		LogLevel:    c.LogLevel,
+		Cache:       c.Cache,
+		failover:    c.failover,
+		dataAge:     c.dataAge,
+		headers:     c.headers.Clone(),
+		// The capacity is limited, so Use on the copy does not append to
+		// the backing array shared with c.
+		middleware: c.middleware[:len(c.middleware):len(c.middleware)],
+	}
+	if c.BaseURL.User != nil {
+		user := *c.BaseURL.User
+		clone.BaseURL.User = &user
+	}
+	if c.RetryPolicy != nil {
+		policy := *c.RetryPolicy
+		clone.RetryPolicy = &policy
+	}
+	clone.bindServices()
+
+	if err := clone.apply(opts); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
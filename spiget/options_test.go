@@ -0,0 +1,94 @@
+package spiget
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestNewInvalidOptions(t *testing.T) {
+	customTransport := &http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"nil HTTP client", []Option{WithHTTPClient(nil)}},
+		{"relative base URL", []Option{WithBaseURL("api.spiget.org/v2/")}},
+		{"base URL scheme", []Option{WithBaseURL("ftp://api.spiget.org/v2/")}},
+		{"unparsable base URL", []Option{WithBaseURL("http://[::1")}},
+		{"empty user agent", []Option{WithUserAgent(" ")}},
+		{"negative timeout", []Option{WithTimeout(-time.Second)}},
+		{"negative retries", []Option{WithRetryPolicy(RetryPolicy{MaxRetries: -1})}},
+		{"negative backoff", []Option{WithRetryPolicy(RetryPolicy{MinBackoff: -time.Second})}},
+		{"backoff bounds", []Option{WithRetryPolicy(RetryPolicy{MinBackoff: time.Minute, MaxBackoff: time.Second})}},
+		{"nil rate limiter", []Option{WithRateLimiter(nil)}},
+		{"nil cache", []Option{WithCache(nil)}},
+		{"nil logger", []Option{WithLogger(nil, LevelInfo)}},
+		{"relative proxy URL", []Option{WithProxy("proxy:8080")}},
+		{"proxy with custom transport", []Option{WithHTTPClient(customTransport), WithProxy("http://proxy:8080")}},
+		{"header name", []Option{WithHeader("X Key", "v")}},
+		{"header value", []Option{WithHeader("X-Key", "a\r\nb")}},
+		{"decode mode", []Option{WithDecoding(DecodeStrict + 1)}},
+		{"no failover URLs", []Option{WithFailover(nil, FailoverOptions{})}},
+		{"negative failover interval", []Option{WithFailover([]string{"https://a/"}, FailoverOptions{CheckInterval: -time.Second})}},
+		{"invalid failover URL", []Option{WithFailover([]string{"a"}, FailoverOptions{})}},
+		{"data age", []Option{WithDataAgeWarning(0, time.Hour)}},
+		{"data age interval", []Option{WithDataAgeWarning(time.Hour, -time.Hour)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.opts...); err == nil {
+				t.Error("New returned no error")
+			}
+		})
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	c, err := New(
+		nil,
+		WithBaseURL("https://spiget.example.com/v2"),
+		WithUserAgent("agent"),
+		WithTimeout(time.Second),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 2}),
+		WithProxy("http://proxy:8080"),
+		WithHeader("X-Key", "a"),
+		WithHeader("X-Key", "b"),
+	)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if got, want := c.BaseURL.String(), "https://spiget.example.com/v2/"; got != want {
+		t.Errorf("BaseURL is %v, want %v", got, want)
+	}
+	if c.UserAgent != "agent" {
+		t.Errorf("UserAgent is %q, want %q", c.UserAgent, "agent")
+	}
+	if c.client.Timeout != time.Second {
+		t.Errorf("timeout is %v, want %v", c.client.Timeout, time.Second)
+	}
+	if c.RetryPolicy == nil || c.RetryPolicy.MaxRetries != 2 {
+		t.Errorf("RetryPolicy is %+v, want MaxRetries 2", c.RetryPolicy)
+	}
+	if _, ok := c.client.Transport.(*http.Transport); !ok {
+		t.Errorf("transport is %T, want *http.Transport", c.client.Transport)
+	}
+	if got := c.headers.Values("X-Key"); len(got) != 2 {
+		t.Errorf("X-Key headers are %q, want both values", got)
+	}
+
+	// WithHTTPClient replaces the client configured by earlier options.
+	c, err = New(WithTimeout(time.Second), WithHTTPClient(&http.Client{}))
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if c.client.Timeout != 0 {
+		t.Errorf("timeout is %v after WithHTTPClient, want 0", c.client.Timeout)
+	}
+}
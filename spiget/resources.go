@@ -2,10 +2,42 @@ package spiget
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"strconv"
 	"strings"
 )
 
+// ErrPremiumResource is returned by the download methods for premium
+// resources, which can only be downloaded from spigotmc.org after purchase.
+var ErrPremiumResource = errors.New("premium resource cannot be downloaded")
+
+// PremiumResourceError reports a download of a premium resource. It matches
+// ErrPremiumResource.
+type PremiumResourceError struct {
+	ResourceID int
+
+	// Err is the *ErrorResponse of the API if it rejected the download, or
+	// nil if the download was redirected to spigotmc.org.
+	Err error
+}
+
+func (e *PremiumResourceError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%v: resource %d: %v", ErrPremiumResource, e.ResourceID, e.Err)
+	}
+	return fmt.Sprintf("%v: resource %d", ErrPremiumResource, e.ResourceID)
+}
+
+// Is reports whether target is ErrPremiumResource.
+func (e *PremiumResourceError) Is(target error) bool {
+	return target == ErrPremiumResource
+}
+
+func (e *PremiumResourceError) Unwrap() error { return e.Err }
+
 // ResourcesService handles communication with the resource related
 // methods of the Spiget API.
 //
@@ -38,6 +70,8 @@ type Resource struct {
 	Updates        []Update          `json:"updates,omitempty"`
 	SourceCodeLink string            `json:"sourceCodeLink,omitempty"`
 	DonationLink   string            `json:"donationLink,omitempty"`
+
+	present fieldMask // fields present in the decoded JSON object
 }
 
 func (r *Resource) String() string {
@@ -50,6 +84,19 @@ type ResourceListOptions struct {
 	ListOptions
 }
 
+func (o ResourceListOptions) validate() error {
+	return validateResourceListOptions(o.ListOptions)
+}
+
+// validateResourceListOptions checks the sort and fields of opts for
+// endpoints returning resources.
+func validateResourceListOptions(opts ListOptions) error {
+	if err := resourceSortFields.validate("resources", opts.Sort, opts.Order); err != nil {
+		return err
+	}
+	return resourceFields.validate("resource", opts.Fields)
+}
+
 func (r *ResourcesService) internalList(ctx context.Context, suffix string, opts *ResourceListOptions) ([]*Resource, *Response, error) {
 	u := "resources/" + suffix
 	u, err := addOptions(u, opts)
@@ -84,12 +131,19 @@ type ResourceListByVersionsOptions struct {
 	ListOptions
 }
 
+func (o ResourceListByVersionsOptions) validate() error {
+	return validateResourceListOptions(o.ListOptions)
+}
+
 // Get resources for the specified version(s).
 //
 // Spiget API docs: https://spiget.org/documentation/#!/resources/get_resources_for_version
 func (r *ResourcesService) ListByVersions(ctx context.Context, versions []string, opts ResourceListByVersionsOptions) ([]*Resource, *Response, error) {
-	versionsStr := strings.Join(versions, ",")
-	u := "resources/for/" + versionsStr
+	escaped := make([]string, len(versions))
+	for i, v := range versions {
+		escaped[i] = pathEscape(v)
+	}
+	u := "resources/for/" + strings.Join(escaped, ",")
 	u, err := addOptions(u, opts)
 	if err != nil {
 		return nil, nil, err
@@ -149,6 +203,37 @@ func (r *ResourcesService) Get(ctx context.Context, id int) (*Resource, *Respons
 	return resource, resp, nil
 }
 
+// ResourceResult is the outcome of fetching a single resource with
+// ResourcesService.GetMany.
+type ResourceResult struct {
+	ID       int
+	Resource *Resource
+	Response *Response
+	Err      error
+}
+
+// GetMany fetches the resources with the given IDs concurrently. Duplicate IDs
+// are only fetched once.
+//
+// The results are returned in the order of ids, each carrying its own error.
+// If ctx is canceled before all resources were fetched, the results fetched
+// so far are returned along with ctx.Err().
+func (r *ResourcesService) GetMany(ctx context.Context, ids []int, opts *BatchOptions) ([]*ResourceResult, error) {
+	batch, err := runBatch(ctx, ids, opts, func(ctx context.Context, id int) (interface{}, *Response, error) {
+		return r.Get(ctx, id)
+	})
+	if batch == nil {
+		return nil, err
+	}
+
+	results := make([]*ResourceResult, len(batch))
+	for i, b := range batch {
+		resource, _ := b.v.(*Resource)
+		results[i] = &ResourceResult{ID: b.id, Resource: resource, Response: b.resp, Err: b.err}
+	}
+	return results, err
+}
+
 // Get the resource author.
 //
 // Spiget API docs: https://spiget.org/documentation/#!/resources/get_resources_resource_author
@@ -172,21 +257,29 @@ func (r *ResourcesService) GetAuthor(ctx context.Context, id int) (*Author, *Res
 //
 // This either redirects to spiget's CDN server (cdn.spiget.org) for a direct download of files hosted on
 // spigotmc.org or to the URL of externally hosted resources The external field of a resource should be
-// checked before downloading, to not receive any unexpected data.
+// checked before downloading, to not receive any unexpected data. Premium
+// resources cannot be downloaded and fail with ErrPremiumResource.
 func (r *ResourcesService) Download(ctx context.Context, id int) (*Response, error) {
-	u := "resources/" + strconv.Itoa(id) + "/download"
-	req, err := r.client.NewRequest("GET", u, nil)
-	if err != nil {
-		return nil, err
-	}
+	return r.download(ctx, id, nil, "resources/"+strconv.Itoa(id)+"/download", nil)
+}
 
-	return r.client.Do(ctx, req, nil)
+// DownloadTo downloads a resource and writes the file to w.
+//
+// The same caveats as for Download apply: the external field of a resource
+// should be checked first, as externally hosted resources might not redirect
+// to a file, and premium resources fail with ErrPremiumResource.
+func (r *ResourcesService) DownloadTo(ctx context.Context, id int, w io.Writer) (*Response, error) {
+	return r.download(ctx, id, nil, "resources/"+strconv.Itoa(id)+"/download", w)
 }
 
 // Get reviews of a resource.
 //
 // Spiget API docs: https://spiget.org/documentation/#!/resources/get_resources_resource_reviews
 func (r *ResourcesService) GetReviews(ctx context.Context, id int, opts ListOptions) ([]*Review, *Response, error) {
+	if err := reviewSortFields.validate("reviews", opts.Sort, opts.Order); err != nil {
+		return nil, nil, err
+	}
+
 	u := "resources/" + strconv.Itoa(id) + "/reviews"
 	u, err := addOptions(u, opts)
 	if err != nil {
@@ -211,6 +304,10 @@ func (r *ResourcesService) GetReviews(ctx context.Context, id int, opts ListOpti
 //
 // Spiget API docs: https://spiget.org/documentation/#!/resources/get_resources_resource_updates
 func (r *ResourcesService) GetUpdates(ctx context.Context, id int, opts ListOptions) ([]*Update, *Response, error) {
+	if err := updateSortFields.validate("updates", opts.Sort, opts.Order); err != nil {
+		return nil, nil, err
+	}
+
 	u := "resources/" + strconv.Itoa(id) + "/updates"
 	u, err := addOptions(u, opts)
 	if err != nil {
@@ -319,26 +416,94 @@ func (r *ResourcesService) GetVersion(ctx context.Context, id int, version int)
 // Spiget API docs: https://spiget.org/documentation/#!/resources/get_resources_resource_versions_version_download
 func (r *ResourcesService) DownloadVersion(ctx context.Context, id int, version int) (*Response, error) {
 	u := "resources/" + strconv.Itoa(id) + "/versions/" + strconv.Itoa(version) + "/download"
+	return r.download(ctx, id, nil, u, nil)
+}
+
+// DownloadVersionTo downloads a specific resource version and writes the file
+// to w.
+//
+// Note: This only follows the stored download location and might not receive a file (i.e. for external resources).
+func (r *ResourcesService) DownloadVersionTo(ctx context.Context, id int, version int, w io.Writer) (*Response, error) {
+	u := "resources/" + strconv.Itoa(id) + "/versions/" + strconv.Itoa(version) + "/download"
+	return r.download(ctx, id, nil, u, w)
+}
+
+// download requests the download URL u of the resource and writes the file
+// to w, if non-nil. Downloads of premium resources, which are redirected to
+// spigotmc.org or rejected by the API, fail with a *PremiumResourceError.
+//
+// External resources may link to pages on spigotmc.org too, so a redirect to
+// such a page is only reported as premium if resource, which is fetched if
+// nil, is premium or hosted on spigotmc.org.
+func (r *ResourcesService) download(ctx context.Context, id int, resource *Resource, u string, w io.Writer) (*Response, error) {
 	req, err := r.client.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return r.client.Do(ctx, req, nil)
+	resp, err := r.client.BareDo(ctx, req)
+	if err != nil {
+		var errResp *ErrorResponse
+		if errors.As(err, &errResp) && strings.Contains(strings.ToLower(errResp.Message), "premium") {
+			return resp, &PremiumResourceError{ResourceID: id, Err: err}
+		}
+		return resp, err
+	}
+	defer resp.Body.Close()
+
+	if isPremiumPage(resp.Response) {
+		if resource == nil {
+			if resource, _, err = r.Get(ctx, id); err != nil {
+				return resp, err
+			}
+		}
+		if resource.Premium || !resource.External {
+			return resp, &PremiumResourceError{ResourceID: id}
+		}
+	}
+	if w != nil {
+		_, err = io.Copy(w, resp.Body)
+	}
+	return resp, err
+}
+
+// isPremiumPage reports whether a download was redirected to a page on
+// spigotmc.org, which happens for premium resources.
+func isPremiumPage(resp *http.Response) bool {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return false
+	}
+	host := strings.ToLower(resp.Request.URL.Hostname())
+	if host != "spigotmc.org" && !strings.HasSuffix(host, ".spigotmc.org") {
+		return false
+	}
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html")
 }
 
 // ResourceSearchOptions specifies the optional parameters to the ResourcesService.Search method.
 type ResourceSearchOptions struct {
-	Field string `url:"field,omitempty"`
+	// Field to search in. Defaults to the resource name.
+	Field SearchField `url:"field,omitempty"`
 
 	ListOptions
 }
 
+func (o ResourceSearchOptions) validate() error {
+	if err := resourceSearchFields.validate("resources", o.Field); err != nil {
+		return err
+	}
+	return validateResourceListOptions(o.ListOptions)
+}
+
 // Search resources.
 //
 // Spiget API docs: https://spiget.org/documentation/#!/resources/get_search_resources_query
 func (r *ResourcesService) Search(ctx context.Context, query string, opts *ResourceSearchOptions) ([]*Resource, *Response, error) {
-	u := "search/resources/" + query
+	if query == "" {
+		return nil, nil, errEmptyQuery
+	}
+
+	u := "search/resources/" + pathEscape(query)
 	u, err := addOptions(u, opts)
 	if err != nil {
 		return nil, nil, err
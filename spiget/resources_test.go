@@ -0,0 +1,83 @@
+package spiget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// hostTransport sends all requests to server, keeping the original URL in
+// the request of the response.
+type hostTransport struct {
+	server *url.URL
+}
+
+func (t hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.URL.Scheme, out.URL.Host = t.server.Scheme, t.server.Host
+	resp, err := http.DefaultTransport.RoundTrip(out)
+	if err == nil {
+		resp.Request = req
+	}
+	return resp, err
+}
+
+func TestDownloadPremium(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/resources/1":
+			fmt.Fprint(w, `{"id": 1, "premium": true}`)
+		case "/resources/2":
+			fmt.Fprint(w, `{"id": 2, "external": true}`)
+		case "/resources/1/download", "/resources/2/download":
+			http.Redirect(w, r, "https://www.spigotmc.org/resources/page/", http.StatusFound)
+		case "/resources/3/download":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "Cannot download premium resource"}`)
+		case "/resources/page/":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, "<html></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client, err := New(WithBaseURL(server.URL+"/"), WithHTTPClient(&http.Client{Transport: hostTransport{serverURL}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Redirected to spigotmc.org.
+	_, err = client.Resources.DownloadTo(ctx, 1, io.Discard)
+	var premiumErr *PremiumResourceError
+	if !errors.Is(err, ErrPremiumResource) || !errors.As(err, &premiumErr) || premiumErr.ResourceID != 1 {
+		t.Errorf("DownloadTo of a premium resource returned error %v, want a *PremiumResourceError", err)
+	}
+
+	// External resources may link to spigotmc.org.
+	if _, err := client.Resources.DownloadTo(ctx, 2, io.Discard); err != nil {
+		t.Errorf("DownloadTo of an external resource returned error %v", err)
+	}
+	if _, err := client.Resources.DownloadVerifiedTo(ctx, &Resource{ID: 2, External: true}, io.Discard); err != nil {
+		t.Errorf("DownloadVerifiedTo of an external resource returned error %v", err)
+	}
+
+	// Rejected by the API.
+	_, err = client.Resources.DownloadTo(ctx, 3, io.Discard)
+	var errResp *ErrorResponse
+	if !errors.Is(err, ErrPremiumResource) || !errors.As(err, &errResp) || errResp.Response.StatusCode != http.StatusForbidden {
+		t.Errorf("DownloadTo of a rejected resource returned error %v, want ErrPremiumResource wrapping a 403 *ErrorResponse", err)
+	}
+
+	if _, err := client.Resources.DownloadVerifiedTo(ctx, &Resource{ID: 4, Premium: true}, io.Discard); !errors.Is(err, ErrPremiumResource) {
+		t.Errorf("DownloadVerifiedTo of a premium resource returned error %v, want %v", err, ErrPremiumResource)
+	}
+}
@@ -0,0 +1,130 @@
+package spiget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how requests failing with a connection error, status
+// 429 or a 5xx status are retried. Other errors are returned immediately.
+// Only idempotent requests are retried by default.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a request is retried.
+	MaxRetries int
+
+	// MinBackoff is the delay before the first retry. It doubles with every
+	// further retry up to MaxBackoff. Defaults to one second.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between retries. If the API asks to retry
+	// after a longer delay via the Retry-After header, the error is returned
+	// instead. Defaults to 30 seconds.
+	MaxBackoff time.Duration
+
+	// Methods are the HTTP methods of the requests which are retried.
+	// Requests with other methods, such as registering a webhook, may have
+	// taken effect before failing and are not retried. Defaults to GET and
+	// HEAD.
+	Methods []string
+}
+
+func (p RetryPolicy) validate() error {
+	if p.MaxRetries < 0 || p.MinBackoff < 0 || p.MaxBackoff < 0 {
+		return errors.New("retry policy must not have negative values")
+	}
+	if p.MaxBackoff > 0 && p.MinBackoff > p.MaxBackoff {
+		return fmt.Errorf("retry policy MinBackoff %v exceeds MaxBackoff %v", p.MinBackoff, p.MaxBackoff)
+	}
+	return nil
+}
+
+// maxBackoff returns MaxBackoff or its default.
+func (p RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff == 0 {
+		return 30 * time.Second
+	}
+	return p.MaxBackoff
+}
+
+// retries reports whether requests with the HTTP method are retried.
+func (p RetryPolicy) retries(method string) bool {
+	if p.Methods == nil {
+		return method == http.MethodGet || method == http.MethodHead
+	}
+	for _, m := range p.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry, starting at 0.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d, limit := p.MinBackoff, p.maxBackoff()
+	if d == 0 {
+		d = time.Second
+	}
+	for i := 0; i < retry && d < limit; i++ {
+		d *= 2
+	}
+	if d > limit {
+		d = limit
+	}
+	return d
+}
+
+// retryable reports whether a request failing with err should be retried.
+func retryable(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && (IsRateLimited(err) || failoverable(ctx, err))
+}
+
+// middleware returns the Middleware retrying requests according to p. Each
+// retry is logged to the Logger of c.
+func (p RetryPolicy) middleware(c *Client, next Doer) Doer {
+	return DoerFunc(func(ctx context.Context, req *http.Request) (*Response, error) {
+		if !p.retries(req.Method) {
+			return next.Do(ctx, req)
+		}
+		for retry := 0; ; retry++ {
+			resp, err := next.Do(ctx, req)
+			if err == nil || retry >= p.MaxRetries || !retryable(ctx, err) {
+				return resp, err
+			}
+
+			// Requests with a body can only be retried if it can be read
+			// again, which is the case for requests made by NewRequest.
+			if req.Body != nil && req.Body != http.NoBody {
+				if req.GetBody == nil {
+					return resp, err
+				}
+				body, bodyErr := req.GetBody()
+				if bodyErr != nil {
+					return resp, err
+				}
+				req.Body = body
+			}
+
+			delay := p.backoff(retry)
+			if after, ok := RetryAfter(err); ok {
+				if after > p.maxBackoff() {
+					return resp, err
+				}
+				delay = after
+			}
+			c.logRetry(ctx, req, retry+1, delay, err)
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return resp, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package spiget
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	errorResponse := func(status int) error {
+		req, _ := http.NewRequest("GET", "https://api.spiget.org/v2/status", nil)
+		return &ErrorResponse{Response: &http.Response{StatusCode: status, Request: req}}
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"rate limited", context.Background(), errorResponse(http.StatusTooManyRequests), true},
+		{"unavailable", context.Background(), errorResponse(http.StatusServiceUnavailable), true},
+		{"connection", context.Background(), errors.New("connection refused"), true},
+		{"not found", context.Background(), errorResponse(http.StatusNotFound), false},
+		{"rate limiter", context.Background(), &rateLimiterError{errors.New("limit")}, false},
+		{"rate limited after cancel", canceled, errorResponse(http.StatusTooManyRequests), false},
+		{"unavailable after cancel", canceled, errorResponse(http.StatusServiceUnavailable), false},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.ctx, tt.err); got != tt.want {
+			t.Errorf("retryable(%s) returned %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRetryMethods(t *testing.T) {
+	calls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.Method]++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		methods []string
+		want    map[string]int
+	}{
+		{nil, map[string]int{"GET": 3, "POST": 1, "DELETE": 1}},
+		{[]string{"get", "DELETE"}, map[string]int{"GET": 3, "POST": 1, "DELETE": 3}},
+	}
+	for _, tt := range tests {
+		calls = make(map[string]int)
+		client, err := New(
+			WithBaseURL(server.URL+"/v2/"),
+			WithRetryPolicy(RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, Methods: tt.methods}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.Background()
+		client.Resources.Get(ctx, 1)
+		client.Webhook.Register(ctx, "https://example.com/hook", []string{"resource_update"})
+		client.Webhook.Delete(ctx, Webhook{ID: "id", Secret: "secret"})
+
+		if !reflect.DeepEqual(calls, tt.want) {
+			t.Errorf("Methods %v: requests by method %v, want %v", tt.methods, calls, tt.want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package spiget
+
+import (
+	"context"
+	"encoding/base64"
+	"math"
+	"sort"
+	"time"
+)
+
+// DecodedMessage returns the text of the review, which Spiget encodes in
+// base64.
+func (r *Review) DecodedMessage() (string, error) {
+	b, err := base64.StdEncoding.DecodeString(r.Message)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Time returns the date of the review.
+func (r *Review) Time() time.Time {
+	return time.Unix(int64(r.Date), 0)
+}
+
+// defaultReviewWindow is the default of ReviewStatsOptions.Window.
+const defaultReviewWindow = 3
+
+// SentimentScorer scores the sentiment of review texts.
+type SentimentScorer interface {
+	// Score returns the sentiment of text, from -1 for negative to +1 for
+	// positive.
+	Score(ctx context.Context, text string) (float64, error)
+}
+
+// SentimentFunc is an adapter to use ordinary functions as SentimentScorer.
+type SentimentFunc func(ctx context.Context, text string) (float64, error)
+
+// Score implements SentimentScorer.
+func (f SentimentFunc) Score(ctx context.Context, text string) (float64, error) {
+	return f(ctx, text)
+}
+
+// ReviewStatsOptions specifies the optional parameters to
+// ResourcesService.ReviewStats and AnalyzeReviews.
+type ReviewStatsOptions struct {
+	// Window is the number of versions or months the rolling averages are
+	// computed over. Defaults to 3.
+	Window int
+
+	// Sentiment, if set, scores the decoded message of every review.
+	// Reviews whose message cannot be decoded are not scored.
+	Sentiment SentimentScorer
+}
+
+// RatingPeriod is the rating of a resource during a version or month.
+type RatingPeriod struct {
+	// Key is the version name, or the month formatted as "2006-01".
+	Key string
+
+	// Start is the date of the first review of the period.
+	Start time.Time
+
+	Reviews int
+	Average float64
+
+	// RollingAverage is the average rating of the reviews in this and the
+	// Window-1 preceding periods.
+	RollingAverage float64
+
+	// Sentiment is the average sentiment score of the reviews, if a
+	// SentimentScorer was given.
+	Sentiment *float64
+
+	ratingSum    float64
+	sentimentSum float64
+	scored       int
+}
+
+// ReviewStats are analytics of the reviews of a resource.
+type ReviewStats struct {
+	Resource  int
+	Reviews   int
+	Reviewers int
+	Average   float64
+
+	// Distribution counts the reviews by rating, e.g. Distribution[4] is
+	// the number of five star reviews.
+	Distribution [5]int
+
+	// ByVersion and ByMonth are in chronological order. Versions are
+	// ordered by their first review.
+	ByVersion []*RatingPeriod
+	ByMonth   []*RatingPeriod
+
+	// Sentiment is the average sentiment score of all reviews, if a
+	// SentimentScorer was given.
+	Sentiment *float64
+
+	// Undecodable is the number of reviews which were not scored because
+	// their message could not be decoded.
+	Undecodable int
+
+	window int
+}
+
+// BadlyReceived returns the versions whose average rating is at least drop
+// stars below the rolling average of the preceding versions. Stats not
+// returned by AnalyzeReviews use the default window.
+func (s *ReviewStats) BadlyReceived(drop float64) []*RatingPeriod {
+	window := s.window
+	if window <= 0 {
+		window = defaultReviewWindow
+	}
+
+	var bad []*RatingPeriod
+	for i, p := range s.ByVersion {
+		if i == 0 {
+			continue
+		}
+		before := rollingAverage(s.ByVersion[:i], window)
+		if before-p.Average >= drop {
+			bad = append(bad, p)
+		}
+	}
+	return bad
+}
+
+// ReviewStats pages through all reviews of a resource and analyzes them.
+func (r *ResourcesService) ReviewStats(ctx context.Context, id int, opts *ReviewStatsOptions) (*ReviewStats, error) {
+	var all []*Review
+	list := ListOptions{Size: 100, Page: 1}
+	for {
+		reviews, resp, err := r.GetReviews(ctx, id, list)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, reviews...)
+		if len(reviews) == 0 || resp.NextPage > resp.LastPage {
+			break
+		}
+		list.Page = resp.NextPage
+	}
+
+	stats, err := AnalyzeReviews(ctx, all, opts)
+	if err != nil {
+		return nil, err
+	}
+	stats.Resource = id
+	return stats, nil
+}
+
+// AnalyzeReviews analyzes reviews of a single resource.
+func AnalyzeReviews(ctx context.Context, reviews []*Review, opts *ReviewStatsOptions) (*ReviewStats, error) {
+	if opts == nil {
+		opts = &ReviewStatsOptions{}
+	}
+	window := opts.Window
+	if window <= 0 {
+		window = defaultReviewWindow
+	}
+
+	reviews = append([]*Review(nil), reviews...)
+	sort.SliceStable(reviews, func(i, j int) bool { return reviews[i].Date < reviews[j].Date })
+
+	stats := &ReviewStats{Reviews: len(reviews), window: window}
+	if len(reviews) > 0 {
+		stats.Resource = reviews[0].Resource
+	}
+	versions := make(map[string]*RatingPeriod)
+	months := make(map[string]*RatingPeriod)
+	reviewers := make(map[int]bool)
+	var ratingSum, sentimentSum float64
+	var scored int
+
+	for _, review := range reviews {
+		rating := review.Rating.Average
+		ratingSum += rating
+		stars := int(math.Round(rating))
+		if stars >= 1 && stars <= 5 {
+			stats.Distribution[stars-1]++
+		}
+		reviewers[review.Author.ID] = true
+
+		var score *float64
+		if opts.Sentiment != nil {
+			text, err := review.DecodedMessage()
+			if err != nil {
+				stats.Undecodable++
+			} else {
+				s, err := opts.Sentiment.Score(ctx, text)
+				if err != nil {
+					return nil, err
+				}
+				score = &s
+				sentimentSum += s
+				scored++
+			}
+		}
+
+		t := review.Time()
+		stats.ByVersion = addToPeriod(stats.ByVersion, versions, review.Version, t, rating, score)
+		stats.ByMonth = addToPeriod(stats.ByMonth, months, t.UTC().Format("2006-01"), t, rating, score)
+	}
+
+	stats.Reviewers = len(reviewers)
+	if len(reviews) > 0 {
+		stats.Average = ratingSum / float64(len(reviews))
+	}
+	if scored > 0 {
+		s := sentimentSum / float64(scored)
+		stats.Sentiment = &s
+	}
+	for _, periods := range [][]*RatingPeriod{stats.ByVersion, stats.ByMonth} {
+		for i, p := range periods {
+			p.Average = p.ratingSum / float64(p.Reviews)
+			if p.scored > 0 {
+				s := p.sentimentSum / float64(p.scored)
+				p.Sentiment = &s
+			}
+			p.RollingAverage = rollingAverage(periods[:i+1], window)
+		}
+	}
+	return stats, nil
+}
+
+// addToPeriod adds a review to the period with the key, appending a new
+// period to periods if needed.
+func addToPeriod(periods []*RatingPeriod, byKey map[string]*RatingPeriod, key string, t time.Time, rating float64, score *float64) []*RatingPeriod {
+	p, ok := byKey[key]
+	if !ok {
+		p = &RatingPeriod{Key: key, Start: t}
+		byKey[key] = p
+		periods = append(periods, p)
+	}
+	p.Reviews++
+	p.ratingSum += rating
+	if score != nil {
+		p.sentimentSum += *score
+		p.scored++
+	}
+	return periods
+}
+
+// rollingAverage returns the average rating of the reviews in the last
+// window periods, whose Average must be set.
+func rollingAverage(periods []*RatingPeriod, window int) float64 {
+	if len(periods) > window {
+		periods = periods[len(periods)-window:]
+	}
+	var sum float64
+	var n int
+	for _, p := range periods {
+		sum += p.Average * float64(p.Reviews)
+		n += p.Reviews
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
@@ -0,0 +1,80 @@
+package spiget
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func review(version string, date time.Time, rating float64, message string) *Review {
+	return &Review{Version: version, Date: int(date.Unix()), Rating: Rating{Average: rating}, Message: message}
+}
+
+func TestAnalyzeReviews(t *testing.T) {
+	base := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	good := base64.StdEncoding.EncodeToString([]byte("good"))
+	bad := base64.StdEncoding.EncodeToString([]byte("bad"))
+	reviews := []*Review{
+		review("1.0", base, 5, good),
+		review("1.0", base.Add(24*time.Hour), 5, good),
+		review("1.1", base.AddDate(0, 1, 0), 4, "not base64!"),
+		review("1.2", base.AddDate(0, 2, 0), 1, bad),
+	}
+	sentiment := SentimentFunc(func(ctx context.Context, text string) (float64, error) {
+		if text == "good" {
+			return 1, nil
+		}
+		return -1, nil
+	})
+
+	stats, err := AnalyzeReviews(context.Background(), reviews, &ReviewStatsOptions{Window: 2, Sentiment: sentiment})
+	if err != nil {
+		t.Fatalf("AnalyzeReviews returned error: %v", err)
+	}
+	if stats.Reviews != 4 || stats.Average != 3.75 || stats.Distribution != [5]int{1, 0, 0, 1, 2} {
+		t.Errorf("AnalyzeReviews returned %d reviews, average %v, distribution %v", stats.Reviews, stats.Average, stats.Distribution)
+	}
+	if stats.Undecodable != 1 {
+		t.Errorf("Undecodable is %d, want 1", stats.Undecodable)
+	}
+	if stats.Sentiment == nil || *stats.Sentiment != 1.0/3 {
+		t.Errorf("Sentiment is %v, want 1/3 of the decodable reviews", stats.Sentiment)
+	}
+	if len(stats.ByVersion) != 3 || stats.ByVersion[1].Sentiment != nil {
+		t.Errorf("ByVersion is %+v, want 3 versions without sentiment for 1.1", stats.ByVersion)
+	}
+	if got := stats.ByVersion[2].RollingAverage; got != 2.5 {
+		t.Errorf("RollingAverage of 1.2 is %v, want 2.5", got)
+	}
+	if len(stats.ByMonth) != 3 || stats.ByMonth[0].Key != "2024-01" {
+		t.Errorf("ByMonth is %+v, want 3 months starting with 2024-01", stats.ByMonth)
+	}
+
+	badly := stats.BadlyReceived(2)
+	if len(badly) != 1 || badly[0].Key != "1.2" {
+		t.Errorf("BadlyReceived returned %+v, want 1.2", badly)
+	}
+}
+
+func TestAnalyzeReviewsScoreError(t *testing.T) {
+	scoreErr := errors.New("scorer down")
+	sentiment := SentimentFunc(func(ctx context.Context, text string) (float64, error) { return 0, scoreErr })
+	reviews := []*Review{review("1.0", time.Now(), 5, "")}
+	if _, err := AnalyzeReviews(context.Background(), reviews, &ReviewStatsOptions{Sentiment: sentiment}); err != scoreErr {
+		t.Errorf("AnalyzeReviews returned error %v, want %v", err, scoreErr)
+	}
+}
+
+func TestBadlyReceivedZeroWindow(t *testing.T) {
+	stats := &ReviewStats{ByVersion: []*RatingPeriod{
+		{Key: "1.0", Reviews: 2, Average: 5},
+		{Key: "1.1", Reviews: 1, Average: 4.5},
+		{Key: "1.2", Reviews: 1, Average: 2},
+	}}
+	badly := stats.BadlyReceived(2)
+	if len(badly) != 1 || badly[0].Key != "1.2" {
+		t.Errorf("BadlyReceived returned %+v, want 1.2", badly)
+	}
+}
@@ -1,9 +1,42 @@
 package spiget
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 type SearchService service
 
+// SearchField is a field resources or authors can be searched by.
+type SearchField string
+
+const (
+	SearchFieldName   SearchField = "name"
+	SearchFieldTag    SearchField = "tag"
+	SearchFieldAuthor SearchField = "author"
+)
+
+var (
+	resourceSearchFields = searchFieldSet{SearchFieldName, SearchFieldTag, SearchFieldAuthor}
+	authorSearchFields   = searchFieldSet{SearchFieldName}
+)
+
+// searchFieldSet is the list of fields an endpoint can be searched by.
+type searchFieldSet []SearchField
+
+// validate returns an error if f is neither empty nor a field of s.
+func (s searchFieldSet) validate(endpoint string, f SearchField) error {
+	if f == "" {
+		return nil
+	}
+	for _, known := range s {
+		if known == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s cannot be searched by %q", ErrInvalidRequest, endpoint, f)
+}
+
 // Search authors.
 //
 // Note: This is actually an alias for the AuthorsService.Search method.
@@ -0,0 +1,57 @@
+package spiget
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// FuzzSearch checks that arbitrary search queries and webhook IDs reach the
+// server as a single, unmodified path segment.
+func FuzzSearch(f *testing.F) {
+	for _, seed := range []string{"PlaceholderAPI", "a/b", "a?b=c", "#tag", "100%", "with space", ".", "..", "../status", "ü"} {
+		f.Add(seed)
+	}
+
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Path
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client, err := NewCustomClient(server.URL+"/v2/", nil)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	f.Fuzz(func(t *testing.T, query string) {
+		ctx := context.Background()
+		if query == "" {
+			if _, _, err := client.Resources.Search(ctx, query, nil); err != errEmptyQuery {
+				t.Errorf("Resources.Search returned error %v, want %v", err, errEmptyQuery)
+			}
+			return
+		}
+
+		if _, _, err := client.Resources.Search(ctx, query, nil); err != nil {
+			t.Fatalf("Resources.Search returned error: %v", err)
+		}
+		if want := "/v2/search/resources/" + query; got != want {
+			t.Errorf("Resources.Search requested path %q, want %q", got, want)
+		}
+
+		if _, _, err := client.Authors.Search(ctx, query, nil); err != nil {
+			t.Fatalf("Authors.Search returned error: %v", err)
+		}
+		if want := "/v2/search/authors/" + query; got != want {
+			t.Errorf("Authors.Search requested path %q, want %q", got, want)
+		}
+
+		client.Webhook.GetStatus(ctx, query)
+		if want := "/v2/webhook/status/" + query; got != want {
+			t.Errorf("Webhook.GetStatus requested path %q, want %q", got, want)
+		}
+	})
+}
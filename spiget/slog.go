@@ -0,0 +1,22 @@
+//go:build go1.21
+// +build go1.21
+
+package spiget
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogLogger returns a Logger writing the records of a Client to l.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) Log(ctx context.Context, level LogLevel, msg string, keyvals ...interface{}) {
+	s.l.Log(ctx, slog.Level(level), msg, keyvals...)
+}
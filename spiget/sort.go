@@ -0,0 +1,137 @@
+package spiget
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortField is a field that the results of a list endpoint can be sorted by.
+// Each endpoint has its own type of fields, see the Resource*, Author*,
+// Review* and Update* constants, so that fields of different endpoints
+// cannot be mixed up in sortFieldSets.
+type SortField interface {
+	sortField() string
+}
+
+// ResourceSortField is a field resources can be sorted by.
+type ResourceSortField string
+
+// AuthorSortField is a field authors can be sorted by.
+type AuthorSortField string
+
+// ReviewSortField is a field reviews can be sorted by.
+type ReviewSortField string
+
+// UpdateSortField is a field updates can be sorted by.
+type UpdateSortField string
+
+func (f ResourceSortField) sortField() string { return string(f) }
+func (f AuthorSortField) sortField() string   { return string(f) }
+func (f ReviewSortField) sortField() string   { return string(f) }
+func (f UpdateSortField) sortField() string   { return string(f) }
+
+// SortOrder is the direction results are sorted in.
+type SortOrder int
+
+const (
+	// Asc sorts results in ascending order.
+	Asc SortOrder = iota
+	// Desc sorts results in descending order.
+	Desc
+)
+
+// Fields resources can be sorted by.
+const (
+	ResourceID          ResourceSortField = "id"
+	ResourceName        ResourceSortField = "name"
+	ResourceTag         ResourceSortField = "tag"
+	ResourceLikes       ResourceSortField = "likes"
+	ResourceDownloads   ResourceSortField = "downloads"
+	ResourceRating      ResourceSortField = "rating.average"
+	ResourceReleaseDate ResourceSortField = "releaseDate"
+	ResourceUpdateDate  ResourceSortField = "updateDate"
+	ResourcePrice       ResourceSortField = "price"
+)
+
+// Fields authors can be sorted by.
+const (
+	AuthorID   AuthorSortField = "id"
+	AuthorName AuthorSortField = "name"
+)
+
+// Fields reviews can be sorted by.
+const (
+	ReviewID      ReviewSortField = "id"
+	ReviewRating  ReviewSortField = "rating.average"
+	ReviewVersion ReviewSortField = "version"
+	ReviewDate    ReviewSortField = "date"
+)
+
+// Fields updates can be sorted by.
+const (
+	UpdateID    UpdateSortField = "id"
+	UpdateTitle UpdateSortField = "title"
+	UpdateLikes UpdateSortField = "likes"
+	UpdateDate  UpdateSortField = "date"
+)
+
+var (
+	resourceSortFields = sortFieldSet{ResourceID, ResourceName, ResourceTag, ResourceLikes, ResourceDownloads, ResourceRating, ResourceReleaseDate, ResourceUpdateDate, ResourcePrice}
+	authorSortFields   = sortFieldSet{AuthorID, AuthorName}
+	reviewSortFields   = sortFieldSet{ReviewID, ReviewRating, ReviewVersion, ReviewDate}
+	updateSortFields   = sortFieldSet{UpdateID, UpdateTitle, UpdateLikes, UpdateDate}
+)
+
+// SortBy returns the value of ListOptions.Sort that sorts results by f in
+// order o, e.g. SortBy(ResourceDownloads, Desc) returns "-downloads".
+func SortBy(f SortField, o SortOrder) string {
+	if o == Desc {
+		return "-" + f.sortField()
+	}
+	return "+" + f.sortField()
+}
+
+// SortResourcesBy is SortBy for resource fields only. It is checked at
+// compile time that f is a field of resources.
+func SortResourcesBy(f ResourceSortField, o SortOrder) string { return SortBy(f, o) }
+
+// SortAuthorsBy is SortBy for author fields only.
+func SortAuthorsBy(f AuthorSortField, o SortOrder) string { return SortBy(f, o) }
+
+// SortReviewsBy is SortBy for review fields only.
+func SortReviewsBy(f ReviewSortField, o SortOrder) string { return SortBy(f, o) }
+
+// SortUpdatesBy is SortBy for update fields only.
+func SortUpdatesBy(f UpdateSortField, o SortOrder) string { return SortBy(f, o) }
+
+// sortFieldSet is the list of fields an endpoint can be sorted by. All fields
+// of a set have the same type.
+type sortFieldSet []SortField
+
+// validate returns an error if sort does not name a field of s, optionally
+// prefixed with "+" or "-", or if order is neither empty, "asc" nor "desc".
+// A prefix contradicting order is rejected as well.
+func (s sortFieldSet) validate(endpoint string, sort, order string) error {
+	switch order {
+	case "", "asc", "desc":
+	default:
+		return fmt.Errorf("%w: invalid sort order %q, must be asc or desc", ErrInvalidRequest, order)
+	}
+
+	if sort == "" {
+		return nil
+	}
+	f := strings.TrimLeft(sort, "+-")
+	if len(sort)-len(f) > 1 {
+		return fmt.Errorf("%w: invalid sort %q", ErrInvalidRequest, sort)
+	}
+	if (sort[0] == '-' && order == "asc") || (sort[0] == '+' && order == "desc") {
+		return fmt.Errorf("%w: sort %q conflicts with order %q", ErrInvalidRequest, sort, order)
+	}
+	for _, known := range s {
+		if known.sortField() == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s cannot be sorted by %q", ErrInvalidRequest, endpoint, f)
+}
@@ -0,0 +1,58 @@
+package spiget
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSortBy(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{SortBy(ResourceDownloads, Desc), "-downloads"},
+		{SortBy(AuthorName, Asc), "+name"},
+		{SortResourcesBy(ResourceRating, Desc), "-rating.average"},
+		{SortAuthorsBy(AuthorID, Asc), "+id"},
+		{SortReviewsBy(ReviewDate, Desc), "-date"},
+		{SortUpdatesBy(UpdateLikes, Asc), "+likes"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("SortBy returned %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestSortFieldSetValidate(t *testing.T) {
+	tests := []struct {
+		sort, order string
+		valid       bool
+	}{
+		{"", "", true},
+		{"", "desc", true},
+		{"downloads", "", true},
+		{"downloads", "desc", true},
+		{"-downloads", "", true},
+		{"-downloads", "desc", true},
+		{"+downloads", "asc", true},
+		{"-downloads", "asc", false},
+		{"+downloads", "desc", false},
+		{"--downloads", "", false},
+		{"date", "", false},
+		{"downloads", "down", false},
+	}
+	for _, tt := range tests {
+		err := resourceSortFields.validate("resources", tt.sort, tt.order)
+		if (err == nil) != tt.valid {
+			t.Errorf("validate(%q, %q) returned error %v, want valid %v", tt.sort, tt.order, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("validate(%q, %q) returned error %v, want %v", tt.sort, tt.order, err, ErrInvalidRequest)
+		}
+	}
+
+	// Fields of other endpoints are rejected if the name differs.
+	if err := reviewSortFields.validate("reviews", SortBy(ResourceDownloads, Desc), ""); err == nil {
+		t.Error("reviews accepted a resource sort field")
+	}
+}
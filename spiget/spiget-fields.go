@@ -0,0 +1,138 @@
+// Code generated by gen-fields; DO NOT EDIT.
+// Instead, please run "go generate ./..." in the spiget directory.
+
+package spiget
+
+import "encoding/json"
+
+// Fields of Author that can be selected via ListOptions.Fields.
+const (
+	AuthorFieldID         Field = "id"
+	AuthorFieldName       Field = "name"
+	AuthorFieldIcon       Field = "icon"
+	AuthorFieldIdentities Field = "identities"
+)
+
+// authorFields lists all known fields of Author.
+var authorFields = fieldSet{
+	AuthorFieldID,
+	AuthorFieldName,
+	AuthorFieldIcon,
+	AuthorFieldIdentities,
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface and records which
+// fields were present in the JSON object.
+func (a *Author) UnmarshalJSON(data []byte) error {
+	type aliasAuthor Author // avoid infinite recursion by using type alias.
+	if err := json.Unmarshal(data, (*aliasAuthor)(a)); err != nil {
+		return err
+	}
+	return a.present.record(authorFields, data)
+}
+
+// HasField reports whether f was present in the JSON object Author was
+// decoded from. Fields trimmed by ListOptions.Fields are reported as absent.
+func (a *Author) HasField(f Field) bool {
+	return a.present.has(authorFields, f)
+}
+
+// Fields of Category that can be selected via ListOptions.Fields.
+const (
+	CategoryFieldID   Field = "id"
+	CategoryFieldName Field = "name"
+)
+
+// categoryFields lists all known fields of Category.
+var categoryFields = fieldSet{
+	CategoryFieldID,
+	CategoryFieldName,
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface and records which
+// fields were present in the JSON object.
+func (c *Category) UnmarshalJSON(data []byte) error {
+	type aliasCategory Category // avoid infinite recursion by using type alias.
+	if err := json.Unmarshal(data, (*aliasCategory)(c)); err != nil {
+		return err
+	}
+	return c.present.record(categoryFields, data)
+}
+
+// HasField reports whether f was present in the JSON object Category was
+// decoded from. Fields trimmed by ListOptions.Fields are reported as absent.
+func (c *Category) HasField(f Field) bool {
+	return c.present.has(categoryFields, f)
+}
+
+// Fields of Resource that can be selected via ListOptions.Fields.
+const (
+	ResourceFieldID             Field = "id"
+	ResourceFieldName           Field = "name"
+	ResourceFieldTag            Field = "tag"
+	ResourceFieldContributors   Field = "contributors"
+	ResourceFieldLikes          Field = "likes"
+	ResourceFieldFile           Field = "file"
+	ResourceFieldTestedVersions Field = "testedVersions"
+	ResourceFieldLinks          Field = "links"
+	ResourceFieldRating         Field = "rating"
+	ResourceFieldReleaseDate    Field = "releaseDate"
+	ResourceFieldUpdateDate     Field = "updateDate"
+	ResourceFieldDownloads      Field = "downloads"
+	ResourceFieldExternal       Field = "external"
+	ResourceFieldIcon           Field = "icon"
+	ResourceFieldPremium        Field = "premium"
+	ResourceFieldPrice          Field = "price"
+	ResourceFieldCurrency       Field = "currency"
+	ResourceFieldAuthor         Field = "author"
+	ResourceFieldCategory       Field = "category"
+	ResourceFieldVersion        Field = "version"
+	ResourceFieldVersions       Field = "versions"
+	ResourceFieldUpdates        Field = "updates"
+	ResourceFieldSourceCodeLink Field = "sourceCodeLink"
+	ResourceFieldDonationLink   Field = "donationLink"
+)
+
+// resourceFields lists all known fields of Resource.
+var resourceFields = fieldSet{
+	ResourceFieldID,
+	ResourceFieldName,
+	ResourceFieldTag,
+	ResourceFieldContributors,
+	ResourceFieldLikes,
+	ResourceFieldFile,
+	ResourceFieldTestedVersions,
+	ResourceFieldLinks,
+	ResourceFieldRating,
+	ResourceFieldReleaseDate,
+	ResourceFieldUpdateDate,
+	ResourceFieldDownloads,
+	ResourceFieldExternal,
+	ResourceFieldIcon,
+	ResourceFieldPremium,
+	ResourceFieldPrice,
+	ResourceFieldCurrency,
+	ResourceFieldAuthor,
+	ResourceFieldCategory,
+	ResourceFieldVersion,
+	ResourceFieldVersions,
+	ResourceFieldUpdates,
+	ResourceFieldSourceCodeLink,
+	ResourceFieldDonationLink,
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface and records which
+// fields were present in the JSON object.
+func (r *Resource) UnmarshalJSON(data []byte) error {
+	type aliasResource Resource // avoid infinite recursion by using type alias.
+	if err := json.Unmarshal(data, (*aliasResource)(r)); err != nil {
+		return err
+	}
+	return r.present.record(resourceFields, data)
+}
+
+// HasField reports whether f was present in the JSON object Resource was
+// decoded from. Fields trimmed by ListOptions.Fields are reported as absent.
+func (r *Resource) HasField(f Field) bool {
+	return r.present.has(resourceFields, f)
+}
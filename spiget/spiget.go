@@ -8,22 +8,52 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
 
+//go:generate go run gen-fields.go
+
 const (
 	defaultBaseURL = "https://api.spiget.org/v2/"
 	userAgent      = "go-spiget/1.0"
 )
 
-var errNonNilContext = errors.New("context must be non-nil")
+var (
+	errNonNilContext = errors.New("context must be non-nil")
+	errEmptyQuery    = fmt.Errorf("%w: search query must be non-empty", ErrInvalidRequest)
+)
+
+// Sentinel errors describing the cause of a failed request. An *ErrorResponse
+// matches the sentinel corresponding to its status code via errors.Is, e.g.
+// errors.Is(err, ErrNotFound) reports whether the requested object does not
+// exist (anymore).
+var (
+	// ErrNotFound is matched by responses with status 404.
+	ErrNotFound = errors.New("not found")
+
+	// ErrRateLimited is matched by responses with status 429. The
+	// RetryAfter field of the ErrorResponse holds the advertised delay.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrServerUnavailable is matched by responses with a 5xx status.
+	// IsServerUnavailable also reports errors reaching the server, which
+	// do not match it.
+	ErrServerUnavailable = errors.New("server unavailable")
+
+	// ErrInvalidRequest is matched by all other responses with a 4xx status.
+	// It is also wrapped by errors returned for invalid options before a
+	// request is sent.
+	ErrInvalidRequest = errors.New("invalid request")
+)
 
 // Client manages communication with the Spiget API.
 type Client struct {
@@ -38,6 +68,32 @@ type Client struct {
 	// User agent used when communicating with the GitHub API.
 	UserAgent string
 
+	// Decoding controls how response bodies are decoded by Do. Defaults to
+	// DecodeDefault.
+	Decoding DecodeMode
+
+	// RateLimiter, if set, is waited on before every request is sent.
+	RateLimiter RateLimiter
+
+	// Logger, if set, receives a record for the start and end of every
+	// request. Successful requests are logged at LogLevel, which defaults to
+	// LevelDebug.
+	Logger   Logger
+	LogLevel LogLevel
+
+	// RetryPolicy, if set, controls retrying requests failing with a
+	// connection error, a rate limit or a server error.
+	RetryPolicy *RetryPolicy
+
+	// Cache, if set, stores the responses of GET requests. Cached responses
+	// carry the X-From-Cache header.
+	Cache Cache
+
+	failover   *failover       // set by WithFailover
+	dataAge    *dataAgeMonitor // set by WithDataAgeWarning
+	headers    http.Header     // added to every request, set by WithHeader
+	middleware []Middleware    // added by Use
+
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
 	// Services used for talking to different parts of the GitHub API.
@@ -53,6 +109,23 @@ type service struct {
 	client *Client
 }
 
+// RateLimiter limits the rate of requests sent by a Client. It is satisfied
+// by *rate.Limiter of golang.org/x/time/rate.
+type RateLimiter interface {
+	// Wait blocks until a request may be sent, or returns an error if ctx
+	// is done first.
+	Wait(ctx context.Context) error
+}
+
+// rateLimiterError wraps an error of RateLimiter.Wait. It is caused by the
+// client, not the API, so such requests are neither retried nor failed over.
+type rateLimiterError struct {
+	err error
+}
+
+func (e *rateLimiterError) Error() string { return e.err.Error() }
+func (e *rateLimiterError) Unwrap() error { return e.err }
+
 // Client returns the http client used to make requests.
 func (c *Client) Client() *http.Client {
 	c.clientMu.Lock()
@@ -71,24 +144,42 @@ type ListOptions struct {
 	// Page index.
 	Page int `url:"page,omitempty"`
 
-	// Field to sort by.
+	// Field to sort by, optionally prefixed with "+" or "-" for ascending or
+	// descending order. Use SortBy or the typed SortResourcesBy, SortAuthorsBy,
+	// SortReviewsBy and SortUpdatesBy to build a valid value. A prefix must
+	// not contradict Order.
 	Sort string `url:"sort,omitempty"`
 
 	// Order of the field to sort by. (asc or desc)
 	Order string `url:"order,omitempty"`
 
-	// Fields to return.
-	Fields []string `url:"fields,omitempty"`
+	// Fields to return. Unknown fields are rejected before the request is
+	// sent for endpoints returning resources, authors or categories. Use
+	// Fields to select them with the typed Field constants.
+	Fields []string `url:"fields,omitempty,comma"`
+}
+
+// optionsValidator is implemented by option structs that check their
+// parameters before a request is created.
+type optionsValidator interface {
+	validate() error
 }
 
 // addOptions adds the parameters in opts as URL query parameters to s. opts
-// must be a struct whose fields may contain "url" tags.
+// must be a struct whose fields may contain "url" tags. If opts implements
+// optionsValidator, it is validated first.
 func addOptions(s string, opts interface{}) (string, error) {
 	v := reflect.ValueOf(opts)
 	if v.Kind() == reflect.Ptr && v.IsNil() {
 		return s, nil
 	}
 
+	if ov, ok := opts.(optionsValidator); ok {
+		if err := ov.validate(); err != nil {
+			return s, err
+		}
+	}
+
 	u, err := url.Parse(s)
 	if err != nil {
 		return s, err
@@ -103,15 +194,33 @@ func addOptions(s string, opts interface{}) (string, error) {
 	return u.String(), nil
 }
 
+// pathEscape escapes s so it can be safely placed inside a single path
+// segment of a relative URL. Unlike url.PathEscape, the dot segments "." and
+// ".." are escaped as well, so they are not removed when the URL is resolved
+// against the BaseURL.
+func pathEscape(s string) string {
+	if s == "." || s == ".." {
+		return strings.Repeat("%2E", len(s))
+	}
+	return url.PathEscape(s)
+}
+
 // NewClient returns a new Spiget API client. If a nil httpClient is
-// provided, a new http.Client will be used.
+// provided, a new http.Client will be used. Use New to configure the client
+// with options.
 func NewClient(httpClient *http.Client) *Client {
 	if httpClient == nil {
 		httpClient = &http.Client{}
 	}
 	baseURL, _ := url.Parse(defaultBaseURL)
 
-	c := &Client{client: httpClient, BaseURL: baseURL, UserAgent: userAgent}
+	c := &Client{client: httpClient, BaseURL: baseURL, UserAgent: userAgent, LogLevel: LevelDebug}
+	c.bindServices()
+	return c
+}
+
+// bindServices points the services of c at c.
+func (c *Client) bindServices() {
 	c.common.client = c
 	c.Authors = (*AuthorsService)(&c.common)
 	c.Categories = (*CategoriesService)(&c.common)
@@ -119,7 +228,6 @@ func NewClient(httpClient *http.Client) *Client {
 	c.Search = (*SearchService)(&c.common)
 	c.Status = (*StatusService)(&c.common)
 	c.Webhook = (*WebhookService)(&c.common)
-	return c
 }
 
 // NewCustomClient returns a new Spiget API client with provided base URL.
@@ -177,6 +285,9 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	if c.UserAgent != "" {
 		req.Header.Set("User-Agent", c.UserAgent)
 	}
+	for key, values := range c.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
 	return req, nil
 }
 
@@ -197,6 +308,14 @@ type Response struct {
 	PrevPage  int
 	FirstPage int
 	LastPage  int
+
+	// Warnings lists the values of the response body which had to be
+	// coerced or dropped when decoding with DecodeLenient.
+	Warnings []DecodeWarning
+
+	// DataAge is set by clients created with WithDataAgeWarning if the data
+	// of the API is stale.
+	DataAge *DataAgeWarning
 }
 
 // newResponse creates a new Response for the provided http.Response.
@@ -234,13 +353,30 @@ func (r *Response) populatePageValues() {
 //
 // The provided ctx must be non-nil, if it is nil an error is returned. If it is
 // canceled or times out, ctx.Err() will be returned.
+//
+// The request is sent through the middleware added with Use.
 func (c *Client) BareDo(ctx context.Context, req *http.Request) (*Response, error) {
 	if ctx == nil {
 		return nil, errNonNilContext
 	}
 
+	c.logRequest(ctx, req)
+	start := time.Now()
+	resp, err := c.doer().Do(ctx, req)
+	c.logResponse(ctx, req, resp, err, time.Since(start))
+	return resp, err
+}
+
+// bareDo sends the request without logging it.
+func (c *Client) bareDo(ctx context.Context, req *http.Request) (*Response, error) {
 	req = withContext(ctx, req)
 
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx); err != nil {
+			return nil, &rateLimiterError{err}
+		}
+	}
+
 	resp, err := c.client.Do(req)
 	if err != nil {
 		// If we got an error, and the context has been canceled,
@@ -291,10 +427,8 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 	case io.Writer:
 		_, err = io.Copy(v, resp.Body)
 	default:
-		decErr := json.NewDecoder(resp.Body).Decode(v)
-		if decErr == io.EOF {
-			decErr = nil // ignore EOF errors caused by empty response body
-		}
+		warnings, decErr := decode(c.Decoding, resp.Body, v)
+		resp.Warnings = warnings
 		if decErr != nil {
 			err = decErr
 		}
@@ -316,18 +450,45 @@ func compareHTTPResponse(r1, r2 *http.Response) bool {
 	return false
 }
 
-// sanitizeURL redacts the client_secret parameter from the URL which may be
-// exposed to the user.
+// sensitiveParams lists the query parameters redacted by sanitizeURL.
+var sensitiveParams = []string{"client_secret", "secret", "token", "access_token", "key", "password"}
+
+// sanitizeURL returns a copy of the URL with credentials redacted, so it may
+// be exposed to the user or logged. This covers user info, sensitive query
+// parameters and the secret in the path of webhook requests.
 func sanitizeURL(uri *url.URL) *url.URL {
 	if uri == nil {
 		return nil
 	}
-	params := uri.Query()
-	if len(params.Get("client_secret")) > 0 {
-		params.Set("client_secret", "REDACTED")
-		uri.RawQuery = params.Encode()
+	u := *uri
+	if u.User != nil {
+		u.User = url.User("REDACTED")
+	}
+
+	params := u.Query()
+	var redacted bool
+	for _, p := range sensitiveParams {
+		if len(params.Get(p)) > 0 {
+			params.Set(p, "REDACTED")
+			redacted = true
+		}
+	}
+	if redacted {
+		u.RawQuery = params.Encode()
+	}
+
+	// webhook/delete/{id}/{secret}. The escaped path is split, as the ID and
+	// secret may contain escaped slashes.
+	escaped := u.EscapedPath()
+	if i := strings.Index(escaped, "webhook/delete/"); i >= 0 {
+		segments := strings.Split(escaped[i:], "/")
+		if len(segments) >= 4 {
+			segments[3] = "REDACTED"
+			u.RawPath = escaped[:i] + strings.Join(segments, "/")
+			u.Path, _ = url.PathUnescape(u.RawPath)
+		}
 	}
-	return uri
+	return &u
 }
 
 // ErrorResponse reports one or more errors caused by an API request.
@@ -335,6 +496,10 @@ type ErrorResponse struct {
 	Response *http.Response // HTTP response that caused this error
 	Message  string         `json:"message"` // error message
 	Errors   []Error        `json:"errors"`  // more detail on individual errors
+
+	// RetryAfter is the delay advertised by the Retry-After header of
+	// rate limited or unavailable responses, or zero if absent.
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (r *ErrorResponse) Error() string {
@@ -343,8 +508,14 @@ func (r *ErrorResponse) Error() string {
 		r.Response.StatusCode, r.Message, r.Errors)
 }
 
-// Is returns whether the provided error equals this error.
+// Is returns whether the provided error equals this error, or whether target
+// is the sentinel error matching the status code of the response.
 func (r *ErrorResponse) Is(target error) bool {
+	switch target {
+	case ErrNotFound, ErrRateLimited, ErrServerUnavailable, ErrInvalidRequest:
+		return r.Response != nil && statusError(r.Response.StatusCode) == target
+	}
+
 	v, ok := target.(*ErrorResponse)
 	if !ok {
 		return false
@@ -367,6 +538,84 @@ func (r *ErrorResponse) Is(target error) bool {
 	return true
 }
 
+// statusError returns the sentinel error for the HTTP status code, or nil if
+// the status does not indicate an error.
+func statusError(code int) error {
+	switch {
+	case code == http.StatusNotFound:
+		return ErrNotFound
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code >= 500:
+		return ErrServerUnavailable
+	case code >= 400:
+		return ErrInvalidRequest
+	}
+	return nil
+}
+
+// IsNotFound reports whether err was caused by a request for an object that
+// does not exist, e.g. a resource deleted on SpigotMC.
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+// IsRateLimited reports whether err was caused by exceeding the rate limit.
+func IsRateLimited(err error) bool { return errors.Is(err, ErrRateLimited) }
+
+// IsServerUnavailable reports whether err was caused by a server side error,
+// or by a failure to reach the server such as a refused connection or a
+// timeout of the HTTP client. Errors of a canceled or expired context are
+// not reported.
+func IsServerUnavailable(err error) bool {
+	if errors.Is(err, ErrServerUnavailable) {
+		return true
+	}
+	// Do returns transport errors as *url.Error, which implements net.Error
+	// itself, so the wrapped error is checked. Errors such as a rejected
+	// redirect or an unsupported scheme do not come from the network.
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		// Do returns ctx.Err() if ctx is done, so timeouts are those of
+		// the HTTP client.
+		if urlErr.Timeout() {
+			return true
+		}
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsInvalidRequest reports whether err was caused by an invalid request.
+func IsInvalidRequest(err error) bool { return errors.Is(err, ErrInvalidRequest) }
+
+// RetryAfter returns the delay advertised by the server before the failed
+// request should be retried. It returns false if err is not an
+// *ErrorResponse with a Retry-After header.
+func RetryAfter(err error) (time.Duration, bool) {
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.RetryAfter <= 0 {
+		return 0, false
+	}
+	return errorResponse.RetryAfter, true
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
 /*
 An Error reports more details on an individual error in an ErrorResponse.
 These are the possible validation error codes:
@@ -412,7 +661,10 @@ func CheckResponse(r *http.Response) error {
 		return nil
 	}
 
-	errorResponse := &ErrorResponse{Response: r}
+	errorResponse := &ErrorResponse{
+		Response:   r,
+		RetryAfter: parseRetryAfter(r.Header.Get("Retry-After")),
+	}
 	data, err := ioutil.ReadAll(r.Body)
 	if err == nil && data != nil {
 		json.Unmarshal(data, errorResponse)
@@ -434,7 +686,8 @@ func parseBoolResponse(err error) (bool, error) {
 		return true, nil
 	}
 
-	if err, ok := err.(*ErrorResponse); ok && err.Response.StatusCode == http.StatusNotFound {
+	var errorResponse *ErrorResponse
+	if errors.As(err, &errorResponse) && errors.Is(errorResponse, ErrNotFound) {
 		// Simply false. In this one case, we do not pass the error through.
 		return false, nil
 	}
@@ -1,6 +1,9 @@
 package spiget
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type StatusService service
 
@@ -88,3 +91,12 @@ func (s *StatusService) Get(ctx context.Context) (*StatusResponse, *Response, er
 
 	return statusResponse, resp, nil
 }
+
+// fetchTime converts a timestamp of Status, which is in milliseconds, to a
+// time.Time. Zero timestamps yield the zero time.
+func fetchTime(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"time"
 
 	"reflect"
 )
 
-var timestampType = reflect.TypeOf(Timestamp{})
+var (
+	timestampType = reflect.TypeOf(Timestamp{})
+	timeType      = reflect.TypeOf(time.Time{})
+)
 
 // Stringify attempts to create a reasonable string representation of types in
 // the GitHub library. It does things like resolve pointers to their values
@@ -50,8 +54,8 @@ func stringifyValue(w io.Writer, val reflect.Value) {
 			w.Write([]byte(v.Type().String()))
 		}
 
-		// special handling of Timestamp values
-		if v.Type() == timestampType {
+		// special handling of Timestamp and time.Time values
+		if v.Type() == timestampType || v.Type() == timeType {
 			fmt.Fprintf(w, "{%s}", v.Interface())
 			return
 		}
@@ -60,6 +64,9 @@ func stringifyValue(w io.Writer, val reflect.Value) {
 
 		var sep bool
 		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
 			fv := v.Field(i)
 			if fv.Kind() == reflect.Ptr && fv.IsNil() {
 				continue
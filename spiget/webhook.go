@@ -20,7 +20,7 @@ type WebhookEvents struct {
 //
 // Spiget API docs: https://spiget.org/documentation/#!/webhook/delete_webhook_delete_id_secret
 func (w *WebhookService) Delete(ctx context.Context, webhook Webhook) (*Response, error) {
-	u := fmt.Sprintf("webhook/delete/%s/%s", webhook.ID, webhook.Secret)
+	u := fmt.Sprintf("webhook/delete/%s/%s", pathEscape(webhook.ID), pathEscape(webhook.Secret))
 	req, err := w.client.NewRequest("DELETE", u, nil)
 	if err != nil {
 		return nil, err
@@ -74,7 +74,7 @@ type WebhookStatus struct {
 //
 // Spiget API docs: https://spiget.org/documentation/#!/webhook/get_webhook_status_id
 func (w *WebhookService) GetStatus(ctx context.Context, id string) (*WebhookStatus, *Response, error) {
-	u := "webhook/status/" + id
+	u := "webhook/status/" + pathEscape(id)
 	req, err := w.client.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, nil, err
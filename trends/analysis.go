@@ -0,0 +1,176 @@
+package trends
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrNotEnoughData is returned if there are too few snapshots to compute a
+// trend.
+var ErrNotEnoughData = errors.New("not enough snapshots")
+
+// Growth is the change of the statistics of a resource between two
+// snapshots.
+type Growth struct {
+	Resource int
+	From, To Snapshot
+
+	Downloads       int
+	DownloadsPerDay float64
+
+	// DownloadGrowth and LikeGrowth are relative to From, e.g. 0.05 for
+	// 5%. They are zero if From is.
+	DownloadGrowth float64
+
+	Likes      int
+	LikeGrowth float64
+
+	RatingChange float64
+}
+
+// GrowthBetween returns the growth from one snapshot of a resource to a
+// later one.
+func GrowthBetween(from, to Snapshot) Growth {
+	g := Growth{
+		Resource:     to.Resource,
+		From:         from,
+		To:           to,
+		Downloads:    to.Downloads - from.Downloads,
+		Likes:        to.Likes - from.Likes,
+		RatingChange: to.Rating - from.Rating,
+	}
+	if days := to.Time.Sub(from.Time).Hours() / 24; days > 0 {
+		g.DownloadsPerDay = float64(g.Downloads) / days
+	}
+	if from.Downloads > 0 {
+		g.DownloadGrowth = float64(g.Downloads) / float64(from.Downloads)
+	}
+	if from.Likes > 0 {
+		g.LikeGrowth = float64(g.Likes) / float64(from.Likes)
+	}
+	return g
+}
+
+// Week are the downloads of a resource in a week.
+type Week struct {
+	// Start is midnight UTC of the Monday starting the week.
+	Start time.Time
+
+	Downloads int
+
+	// Partial reports whether the snapshots only cover part of the week,
+	// e.g. of the current week.
+	Partial bool
+}
+
+// weekStart returns the start of the week of t.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	days := (int(t.Weekday()) + 6) % 7 // days since Monday
+	return time.Date(t.Year(), t.Month(), t.Day()-days, 0, 0, 0, 0, time.UTC)
+}
+
+// WeeklyDownloads returns the downloads per week of the snapshots of a
+// resource, which must be ordered oldest first. The downloads of a week are
+// counted from the last snapshot before it to the last snapshot in it.
+func WeeklyDownloads(snapshots []Snapshot) []Week {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	last := snapshots[len(snapshots)-1]
+
+	var weeks []Week
+	for start := weekStart(snapshots[0].Time); !start.After(last.Time); start = start.Add(week) {
+		end := start.Add(week)
+		base, ok := before(snapshots, start)
+		partial := !ok || end.After(last.Time)
+		if !ok {
+			base = snapshots[0]
+		}
+		current, _ := before(snapshots, end)
+		weeks = append(weeks, Week{Start: start, Downloads: current.Downloads - base.Downloads, Partial: partial})
+	}
+	return weeks
+}
+
+// before returns the last snapshot taken before t.
+func before(snapshots []Snapshot, t time.Time) (Snapshot, bool) {
+	i := sort.Search(len(snapshots), func(i int) bool { return !snapshots[i].Time.Before(t) })
+	if i == 0 {
+		return Snapshot{}, false
+	}
+	return snapshots[i-1], true
+}
+
+// Rank is the position of a resource in its category by weekly downloads.
+type Rank struct {
+	Resource int
+	Category int
+
+	// Rank starts at 1. PreviousRank is the rank in the week before, or 0
+	// if the resource was not tracked then.
+	Rank         int
+	PreviousRank int
+
+	Downloads         int
+	PreviousDownloads int
+}
+
+// Change returns the number of positions the resource climbed since the
+// previous week, or 0 if it was not ranked then.
+func (r Rank) Change() int {
+	if r.PreviousRank == 0 {
+		return 0
+	}
+	return r.PreviousRank - r.Rank
+}
+
+// RankCategory ranks the resources of the category by their downloads in
+// the week before now, given the snapshots of each resource ordered oldest
+// first. Resources lacking a snapshot from a week ago are not ranked.
+func RankCategory(category int, series map[int][]Snapshot, now time.Time) []Rank {
+	var ranks []Rank
+	var previous []Rank
+	for id, snapshots := range series {
+		latest, ok := before(snapshots, now.Add(time.Nanosecond))
+		if !ok || latest.Category != category {
+			continue
+		}
+		weekAgo, ok := before(snapshots, now.Add(-week).Add(time.Nanosecond))
+		if !ok {
+			continue
+		}
+		r := Rank{Resource: id, Category: category, Downloads: latest.Downloads - weekAgo.Downloads}
+		if twoWeeksAgo, ok := before(snapshots, now.Add(-2*week).Add(time.Nanosecond)); ok {
+			r.PreviousDownloads = weekAgo.Downloads - twoWeeksAgo.Downloads
+			previous = append(previous, r)
+		}
+		ranks = append(ranks, r)
+	}
+
+	rank(previous, func(r Rank) int { return r.PreviousDownloads })
+	previousRanks := make(map[int]int, len(previous))
+	for _, r := range previous {
+		previousRanks[r.Resource] = r.Rank
+	}
+	rank(ranks, func(r Rank) int { return r.Downloads })
+	for i := range ranks {
+		ranks[i].PreviousRank = previousRanks[ranks[i].Resource]
+	}
+	return ranks
+}
+
+// rank sorts ranks by downloads, descending, and numbers them.
+func rank(ranks []Rank, downloads func(Rank) int) {
+	sort.Slice(ranks, func(i, j int) bool {
+		di, dj := downloads(ranks[i]), downloads(ranks[j])
+		if di != dj {
+			return di > dj
+		}
+		return ranks[i].Resource < ranks[j].Resource
+	})
+	for i := range ranks {
+		ranks[i].Rank = i + 1
+	}
+}
@@ -0,0 +1,99 @@
+package trends
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGrowthBetween(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	from := Snapshot{Time: base, Resource: 1, Downloads: 1000, Likes: 10, Rating: 4}
+	to := Snapshot{Time: base.Add(48 * time.Hour), Resource: 1, Downloads: 1100, Likes: 15, Rating: 4.5}
+
+	g := GrowthBetween(from, to)
+	want := Growth{
+		Resource: 1, From: from, To: to,
+		Downloads: 100, DownloadsPerDay: 50, DownloadGrowth: 0.1,
+		Likes: 5, LikeGrowth: 0.5, RatingChange: 0.5,
+	}
+	if !reflect.DeepEqual(g, want) {
+		t.Errorf("GrowthBetween returned %+v, want %+v", g, want)
+	}
+
+	if g := GrowthBetween(Snapshot{Time: base}, Snapshot{Time: base, Downloads: 5}); g.DownloadGrowth != 0 || g.DownloadsPerDay != 0 {
+		t.Errorf("GrowthBetween from zero returned %+v", g)
+	}
+}
+
+func TestWeeklyDownloads(t *testing.T) {
+	monday := time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	snapshots := []Snapshot{
+		{Time: monday.Add(2 * day), Downloads: 100}, // Wednesday of week 1
+		{Time: monday.Add(6 * day), Downloads: 130}, // Sunday of week 1
+		{Time: monday.Add(8 * day), Downloads: 150}, // Tuesday of week 2
+		{Time: monday.Add(13 * day), Downloads: 200},
+		{Time: monday.Add(15 * day), Downloads: 210}, // Tuesday of week 3
+	}
+
+	got := WeeklyDownloads(snapshots)
+	want := []Week{
+		{Start: monday, Downloads: 30, Partial: true},
+		{Start: monday.Add(7 * day), Downloads: 70},
+		{Start: monday.Add(14 * day), Downloads: 10, Partial: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WeeklyDownloads returned %+v, want %+v", got, want)
+	}
+	if got := WeeklyDownloads(nil); got != nil {
+		t.Errorf("WeeklyDownloads(nil) returned %+v", got)
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		at := monday.Add(time.Duration(i)*24*time.Hour + 13*time.Hour)
+		if got := weekStart(at); !got.Equal(monday) {
+			t.Errorf("weekStart(%v) = %v, want %v", at, got, monday)
+		}
+	}
+}
+
+func TestRankCategory(t *testing.T) {
+	now := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	series := func(category int, downloads ...int) []Snapshot {
+		// Downloads two weeks ago, a week ago and now.
+		var s []Snapshot
+		for i, d := range downloads {
+			s = append(s, Snapshot{Time: now.Add(time.Duration(i-len(downloads)+1) * week), Category: category, Downloads: d})
+		}
+		return s
+	}
+
+	ranks := RankCategory(4, map[int][]Snapshot{
+		1: series(4, 0, 100, 150), // 100, then 50
+		2: series(4, 0, 10, 210),  // 10, then 200
+		3: series(4, 0, 50, 100),  // 50, then 50
+		4: series(4, 5, 25),       // new: 20
+		5: series(5, 0, 1, 1000),  // other category
+		6: series(4, 0),           // no snapshot a week ago
+	}, now)
+
+	want := []Rank{
+		{Resource: 2, Category: 4, Rank: 1, PreviousRank: 3, Downloads: 200, PreviousDownloads: 10},
+		{Resource: 1, Category: 4, Rank: 2, PreviousRank: 1, Downloads: 50, PreviousDownloads: 100},
+		{Resource: 3, Category: 4, Rank: 3, PreviousRank: 2, Downloads: 50, PreviousDownloads: 50},
+		{Resource: 4, Category: 4, Rank: 4, Downloads: 20},
+	}
+	if !reflect.DeepEqual(ranks, want) {
+		t.Errorf("RankCategory returned %+v, want %+v", ranks, want)
+	}
+	changes := []int{2, -1, -1, 0}
+	for i, r := range ranks {
+		if r.Change() != changes[i] {
+			t.Errorf("resource %d changed %d ranks, want %d", r.Resource, r.Change(), changes[i])
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Package sqlitestore provides a trends.Store backed by SQLite.
+//
+// It is a separate module so that the SQLite driver is only required by
+// programs using it.
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sunxyw/go-spiget/trends"
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS snapshots (
+	time         INTEGER NOT NULL,
+	resource     INTEGER NOT NULL,
+	category     INTEGER NOT NULL,
+	downloads    INTEGER NOT NULL,
+	likes        INTEGER NOT NULL,
+	rating       REAL    NOT NULL,
+	rating_count INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS snapshots_resource_time ON snapshots (resource, time);
+`
+
+// Store stores snapshots in the table "snapshots" of a SQLite database.
+type Store struct {
+	db *sql.DB
+}
+
+var _ trends.Store = (*Store)(nil)
+
+// Open opens the SQLite database at path, creating it if needed.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New returns a store using db, creating the table if needed.
+func New(db *sql.DB) (*Store, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Append implements trends.Store.
+func (s *Store) Append(ctx context.Context, snapshots []trends.Snapshot) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO snapshots
+		(time, resource, category, downloads, likes, rating, rating_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, snap := range snapshots {
+		_, err := stmt.ExecContext(ctx, snap.Time.UnixMilli(), snap.Resource, snap.Category,
+			snap.Downloads, snap.Likes, snap.Rating, snap.RatingCount)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Snapshots implements trends.Store.
+func (s *Store) Snapshots(ctx context.Context, resource int, from, to time.Time) ([]trends.Snapshot, error) {
+	var min, max int64 = 0, 1<<63 - 1
+	if !from.IsZero() {
+		min = from.UnixMilli()
+	}
+	if !to.IsZero() {
+		max = to.UnixMilli()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT time, resource, category, downloads, likes, rating, rating_count
+		FROM snapshots WHERE resource = ? AND time BETWEEN ? AND ? ORDER BY time`, resource, min, max)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []trends.Snapshot
+	for rows.Next() {
+		var snap trends.Snapshot
+		var ms int64
+		err := rows.Scan(&ms, &snap.Resource, &snap.Category, &snap.Downloads, &snap.Likes, &snap.Rating, &snap.RatingCount)
+		if err != nil {
+			return nil, err
+		}
+		snap.Time = time.UnixMilli(ms).UTC()
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,20 @@
+package sqlitestore
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sunxyw/go-spiget/trends"
+	"github.com/sunxyw/go-spiget/trends/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) trends.Store {
+		s, err := Open(filepath.Join(t.TempDir(), "snapshots.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	})
+}
@@ -0,0 +1,170 @@
+package trends
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Snapshot are the statistics of a resource at a point in time. Stores keep
+// Time with millisecond precision, in UTC.
+type Snapshot struct {
+	Time        time.Time
+	Resource    int
+	Category    int
+	Downloads   int
+	Likes       int
+	Rating      float64
+	RatingCount int
+}
+
+// Store is a time series of snapshots. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Append stores the snapshots.
+	Append(ctx context.Context, snapshots []Snapshot) error
+
+	// Snapshots returns the snapshots of the resource taken from from to to
+	// inclusive, oldest first. Zero times are unbounded.
+	Snapshots(ctx context.Context, resource int, from, to time.Time) ([]Snapshot, error)
+}
+
+// csvTimeFormat is RFC 3339 with millisecond precision.
+const csvTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// csvHeader is the first row of CSV stores.
+var csvHeader = []string{"time", "resource", "category", "downloads", "likes", "rating", "rating_count"}
+
+// CSVStore stores snapshots in a CSV file, one row per snapshot. Queries
+// read the whole file, so it suits a few resources tracked for some years.
+type CSVStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewCSVStore returns a store using the CSV file at path, which is created
+// on the first Append.
+func NewCSVStore(path string) *CSVStore {
+	return &CSVStore{path: path}
+}
+
+// Append implements Store.
+func (s *CSVStore) Append(ctx context.Context, snapshots []Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	if info.Size() == 0 {
+		w.Write(csvHeader)
+	}
+	for _, snap := range snapshots {
+		w.Write([]string{
+			snap.Time.UTC().Format(csvTimeFormat),
+			strconv.Itoa(snap.Resource),
+			strconv.Itoa(snap.Category),
+			strconv.Itoa(snap.Downloads),
+			strconv.Itoa(snap.Likes),
+			strconv.FormatFloat(snap.Rating, 'f', -1, 64),
+			strconv.Itoa(snap.RatingCount),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Snapshots implements Store.
+func (s *CSVStore) Snapshots(ctx context.Context, resource int, from, to time.Time) ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Bounds are compared at the precision of the stored times.
+	from, to = from.Truncate(time.Millisecond), to.Truncate(time.Millisecond)
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = len(csvHeader)
+	var snapshots []Snapshot
+	for line := 1; ; line++ {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 {
+			continue
+		}
+		snap, err := parseCSVRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.path, line, err)
+		}
+		if snap.Resource != resource || inRange(snap.Time, from, to) != 0 {
+			continue
+		}
+		snapshots = append(snapshots, snap)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Time.Before(snapshots[j].Time) })
+	return snapshots, nil
+}
+
+func parseCSVRow(row []string) (Snapshot, error) {
+	var snap Snapshot
+	var err error
+	if snap.Time, err = time.Parse(time.RFC3339, row[0]); err != nil {
+		return snap, err
+	}
+	snap.Time = snap.Time.UTC()
+	ints := []*int{&snap.Resource, &snap.Category, &snap.Downloads, &snap.Likes}
+	for i, p := range ints {
+		if *p, err = strconv.Atoi(row[i+1]); err != nil {
+			return snap, err
+		}
+	}
+	if snap.Rating, err = strconv.ParseFloat(row[5], 64); err != nil {
+		return snap, err
+	}
+	snap.RatingCount, err = strconv.Atoi(row[6])
+	return snap, err
+}
+
+// inRange returns -1 if t is before from, +1 if it is after to and 0
+// otherwise. Zero bounds are open.
+func inRange(t, from, to time.Time) int {
+	switch {
+	case !from.IsZero() && t.Before(from):
+		return -1
+	case !to.IsZero() && t.After(to):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,15 @@
+package trends_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sunxyw/go-spiget/trends"
+	"github.com/sunxyw/go-spiget/trends/storetest"
+)
+
+func TestCSVStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) trends.Store {
+		return trends.NewCSVStore(filepath.Join(t.TempDir(), "snapshots.csv"))
+	})
+}
@@ -0,0 +1,99 @@
+// Package storetest checks implementations of trends.Store, so that all
+// stores give the same results for the same data.
+package storetest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sunxyw/go-spiget/trends"
+)
+
+// Run tests the store returned by newStore, which must be empty.
+func Run(t *testing.T, newStore func(t *testing.T) trends.Store) {
+	ctx := context.Background()
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Empty", func(t *testing.T) {
+		s := newStore(t)
+		got, err := s.Snapshots(ctx, 1, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatalf("Snapshots returned error: %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("Snapshots of an empty store returned %v", got)
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		s := newStore(t)
+		in := []trends.Snapshot{
+			{Time: base.Add(time.Hour), Resource: 1, Category: 4, Downloads: 20, Likes: 3, Rating: 4.25, RatingCount: 8},
+			{Time: base, Resource: 1, Category: 4, Downloads: 10, Likes: 2, Rating: 4.5, RatingCount: 6},
+			{Time: base, Resource: 2, Category: 5, Downloads: 99},
+		}
+		if err := s.Append(ctx, in[:1]); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+		if err := s.Append(ctx, in[1:]); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+
+		got, err := s.Snapshots(ctx, 1, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatalf("Snapshots returned error: %v", err)
+		}
+		if want := []trends.Snapshot{in[1], in[0]}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Snapshots returned %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("Precision", func(t *testing.T) {
+		s := newStore(t)
+		in := trends.Snapshot{Time: base.Add(1234567 * time.Microsecond).In(time.FixedZone("X", 3600)), Resource: 1}
+		if err := s.Append(ctx, []trends.Snapshot{in}); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+		got, err := s.Snapshots(ctx, 1, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatalf("Snapshots returned error: %v", err)
+		}
+		want := base.Add(1234 * time.Millisecond)
+		if len(got) != 1 || got[0].Time != want {
+			t.Errorf("Snapshots returned %+v, want time %v", got, want)
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		s := newStore(t)
+		var in []trends.Snapshot
+		for i := 0; i < 5; i++ {
+			in = append(in, trends.Snapshot{Time: base.Add(time.Duration(i) * time.Hour), Resource: 1, Downloads: i})
+		}
+		if err := s.Append(ctx, in); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+
+		tests := []struct {
+			from, to time.Time
+			want     []trends.Snapshot
+		}{
+			{base.Add(time.Hour), base.Add(3 * time.Hour), in[1:4]},
+			{base.Add(time.Hour + time.Minute), time.Time{}, in[2:]},
+			{time.Time{}, base.Add(time.Hour - time.Minute), in[:1]},
+			{base.Add(time.Hour + 500*time.Microsecond), base.Add(time.Hour), in[1:2]},
+			{base.Add(10 * time.Hour), time.Time{}, nil},
+		}
+		for _, tt := range tests {
+			got, err := s.Snapshots(ctx, 1, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Snapshots returned error: %v", err)
+			}
+			if len(got) != len(tt.want) || len(got) > 0 && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Snapshots(%v, %v) returned %+v, want %+v", tt.from, tt.to, got, tt.want)
+			}
+		}
+	})
+}
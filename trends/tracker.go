@@ -0,0 +1,149 @@
+// Package trends tracks the downloads, likes and ratings of Spiget
+// resources over time.
+//
+// Spiget only reports the current statistics of a resource. A Tracker
+// periodically records them in a Store, from which growth rates, weekly
+// downloads and rankings within categories are computed, e.g. to spot
+// rising plugins.
+package trends
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+const (
+	defaultInterval = 24 * time.Hour
+	week            = 7 * 24 * time.Hour
+)
+
+// Tracker snapshots resources into a store.
+type Tracker struct {
+	Spiget *spiget.Client
+	Store  Store
+
+	// Resources are the IDs of the tracked resources.
+	Resources []int
+
+	// Interval between snapshots taken by Run. Defaults to a day, as
+	// Spiget itself only refreshes resources every few hours.
+	Interval time.Duration
+
+	// OnError, if set, is called with the errors of snapshots taken by Run,
+	// which then continues. If nil, Run returns the first error.
+	OnError func(error)
+}
+
+// Snapshot fetches the tracked resources and appends their statistics to
+// the store. Resources that could not be fetched are skipped, and the first
+// of their errors is returned after storing the others.
+func (t *Tracker) Snapshot(ctx context.Context) ([]Snapshot, error) {
+	results, err := t.Spiget.Resources.GetMany(ctx, t.Resources, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var snapshots []Snapshot
+	var fetchErr error
+	for _, res := range results {
+		if res.Err != nil {
+			if fetchErr == nil {
+				fetchErr = fmt.Errorf("resource %d: %w", res.ID, res.Err)
+			}
+			continue
+		}
+		r := res.Resource
+		snapshots = append(snapshots, Snapshot{
+			Time:        now,
+			Resource:    r.ID,
+			Category:    r.Category.ID,
+			Downloads:   r.Downloads,
+			Likes:       r.Likes,
+			Rating:      r.Rating.Average,
+			RatingCount: r.Rating.Count,
+		})
+	}
+	if len(snapshots) > 0 {
+		if err := t.Store.Append(ctx, snapshots); err != nil {
+			return nil, err
+		}
+	}
+	return snapshots, fetchErr
+}
+
+// Run takes a snapshot every Interval, starting immediately, until ctx is
+// done.
+func (t *Tracker) Run(ctx context.Context) error {
+	interval := t.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := t.Snapshot(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if t.OnError == nil {
+				return err
+			}
+			t.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Growth returns the growth of the resource over the last period.
+func (t *Tracker) Growth(ctx context.Context, resource int, period time.Duration) (*Growth, error) {
+	now := time.Now()
+	snapshots, err := t.Store.Snapshots(ctx, resource, now.Add(-period), now)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) < 2 {
+		return nil, ErrNotEnoughData
+	}
+	g := GrowthBetween(snapshots[0], snapshots[len(snapshots)-1])
+	return &g, nil
+}
+
+// WeeklyDownloads returns the downloads of the resource in the last weeks,
+// oldest first.
+func (t *Tracker) WeeklyDownloads(ctx context.Context, resource int, weeks int) ([]Week, error) {
+	// One more week is needed for the downloads before the first week.
+	from := weekStart(time.Now()).Add(-time.Duration(weeks) * week)
+	snapshots, err := t.Store.Snapshots(ctx, resource, from, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	w := WeeklyDownloads(snapshots)
+	if len(w) > weeks {
+		w = w[len(w)-weeks:]
+	}
+	return w, nil
+}
+
+// Ranking ranks the tracked resources of the category by their downloads
+// in the last week.
+func (t *Tracker) Ranking(ctx context.Context, category int) ([]Rank, error) {
+	now := time.Now()
+	series := make(map[int][]Snapshot)
+	for _, id := range t.Resources {
+		snapshots, err := t.Store.Snapshots(ctx, id, now.Add(-3*week), now)
+		if err != nil {
+			return nil, err
+		}
+		series[id] = snapshots
+	}
+	return RankCategory(category, series, now), nil
+}
@@ -0,0 +1,106 @@
+package trends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sunxyw/go-spiget/spiget"
+)
+
+func TestTracker(t *testing.T) {
+	downloads := 100
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/resources/1" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"id": 1, "downloads": %d, "likes": 3, "rating": {"average": 4.5, "count": 2}, "category": {"id": 4}}`, downloads)
+	}))
+	defer server.Close()
+
+	client, err := spiget.New(spiget.WithBaseURL(server.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &Tracker{
+		Spiget:    client,
+		Store:     NewCSVStore(filepath.Join(t.TempDir(), "snapshots.csv")),
+		Resources: []int{1, 2},
+	}
+	ctx := context.Background()
+
+	if _, err := tracker.Growth(ctx, 1, time.Hour); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Growth without snapshots returned error %v, want %v", err, ErrNotEnoughData)
+	}
+
+	// Resource 2 does not exist, but resource 1 is still stored.
+	snapshots, err := tracker.Snapshot(ctx)
+	if !spiget.IsNotFound(err) {
+		t.Errorf("Snapshot returned error %v, want a not found error", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Resource != 1 || snapshots[0].Category != 4 || snapshots[0].Rating != 4.5 {
+		t.Errorf("Snapshot returned %+v", snapshots)
+	}
+
+	downloads = 150
+	tracker.Resources = []int{1}
+	if _, err := tracker.Snapshot(ctx); err != nil {
+		t.Fatalf("Snapshot returned error: %v", err)
+	}
+	g, err := tracker.Growth(ctx, 1, time.Hour)
+	if err != nil {
+		t.Fatalf("Growth returned error: %v", err)
+	}
+	if g.Downloads != 50 || g.DownloadGrowth != 0.5 {
+		t.Errorf("Growth returned %+v, want 50 downloads", g)
+	}
+
+	weeks, err := tracker.WeeklyDownloads(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("WeeklyDownloads returned error: %v", err)
+	}
+	if len(weeks) != 1 || weeks[0].Downloads != 50 || !weeks[0].Partial {
+		t.Errorf("WeeklyDownloads returned %+v", weeks)
+	}
+}
+
+func TestTrackerRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client, err := spiget.New(spiget.WithBaseURL(server.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &Tracker{
+		Spiget:    client,
+		Store:     NewCSVStore(filepath.Join(t.TempDir(), "snapshots.csv")),
+		Resources: []int{1},
+		Interval:  time.Millisecond,
+	}
+	if err := tracker.Run(context.Background()); !spiget.IsNotFound(err) {
+		t.Errorf("Run returned error %v, want a not found error", err)
+	}
+
+	var errs int
+	ctx, cancel := context.WithCancel(context.Background())
+	tracker.OnError = func(err error) {
+		if errs++; errs == 3 {
+			cancel()
+		}
+	}
+	if err := tracker.Run(ctx); err != context.Canceled {
+		t.Errorf("Run returned error %v, want %v", err, context.Canceled)
+	}
+	if errs < 3 {
+		t.Errorf("OnError was called %d times, want at least 3", errs)
+	}
+}